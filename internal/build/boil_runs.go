@@ -1,26 +1,93 @@
 package build
 
 import (
+	"regexp"
+	"strings"
+
+	"github.com/alessio/shellescape"
+
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
+// A run step can refer to the container paths of the files that changed (and
+// matched its triggers, if it has any) as $CHANGED_FILES or ${CHANGED_FILES}.
+//
+// How the paths are joined depends on the form of the command:
+//   - In a shell command (`sh -c "..."`), each path is shell-quoted and the
+//     paths are joined with spaces. Don't put quotes around the variable.
+//   - In an argv command, an arg that's exactly $CHANGED_FILES expands to one
+//     arg per path. Anywhere else, the paths are joined with spaces as-is.
+//
+// With ForEachFile, the command runs once per file, and $CHANGED_FILES is that file.
+var changedFilesRE = regexp.MustCompile(`\$(CHANGED_FILES\b|\{CHANGED_FILES\})`)
+var changedFilesArgRE = regexp.MustCompile(`^\$(CHANGED_FILES|\{CHANGED_FILES\})$`)
+
 func BoilRuns(runs []model.Run, pathMappings []PathMapping) ([]model.Cmd, error) {
 	res := []model.Cmd{}
-	localPaths := PathMappingsToLocalPaths(pathMappings)
 	for _, run := range runs {
-		if run.Triggers.Empty() {
-			res = append(res, run.Cmd)
+		changed := pathMappings
+		if !run.Triggers.Empty() {
+			var err error
+			changed, err = pathMappingsMatchingTriggers(run.Triggers, pathMappings)
+			if err != nil {
+				return nil, err
+			}
+			if len(changed) == 0 {
+				continue
+			}
+		}
+
+		if !run.ForEachFile {
+			res = append(res, substituteChangedFiles(run.Cmd, PathMappingsToContainerPaths(changed)))
 			continue
 		}
 
-		anyMatch, _, err := run.Triggers.AnyMatch(localPaths)
+		for _, p := range PathMappingsToContainerPaths(changed) {
+			res = append(res, substituteChangedFiles(run.Cmd, []string{p}))
+		}
+	}
+	return res, nil
+}
+
+func pathMappingsMatchingTriggers(triggers model.PathSet, pathMappings []PathMapping) ([]PathMapping, error) {
+	var res []PathMapping
+	for _, pm := range pathMappings {
+		match, _, err := triggers.AnyMatch([]string{pm.LocalPath})
 		if err != nil {
 			return nil, err
 		}
-
-		if anyMatch {
-			res = append(res, run.Cmd)
+		if match {
+			res = append(res, pm)
 		}
 	}
 	return res, nil
 }
+
+func substituteChangedFiles(cmd model.Cmd, paths []string) model.Cmd {
+	// Like IsShellStandardForm, but multi-line scripts count, too.
+	isShell := len(cmd.Argv) == 3 && cmd.Argv[0] == "sh" && cmd.Argv[1] == "-c"
+	if isShell {
+		quoted := make([]string, len(paths))
+		for i, p := range paths {
+			quoted[i] = shellescape.Quote(p)
+		}
+		script := replaceChangedFiles(cmd.Argv[2], strings.Join(quoted, " "))
+		cmd.Argv = []string{cmd.Argv[0], cmd.Argv[1], script}
+		return cmd
+	}
+
+	var argv []string
+	for _, arg := range cmd.Argv {
+		if changedFilesArgRE.MatchString(arg) {
+			argv = append(argv, paths...)
+			continue
+		}
+		argv = append(argv, replaceChangedFiles(arg, strings.Join(paths, " ")))
+	}
+	cmd.Argv = argv
+	return cmd
+}
+
+func replaceChangedFiles(s string, value string) string {
+	return changedFilesRE.ReplaceAllLiteralString(s, value)
+}
@@ -193,6 +193,103 @@ func TestBoilRunsManyTriggersManyFiles(t *testing.T) {
 	assert.ElementsMatch(t, expected, actual)
 }
 
+func TestBoilRunsChangedFilesJoined(t *testing.T) {
+	wd := AbsPath("test")
+	runs := []model.Run{
+		model.Run{
+			Cmd:      model.ToUnixCmd("protoc $CHANGED_FILES && echo ${CHANGED_FILES}"),
+			Triggers: model.NewPathSet([]string{"protos"}, wd),
+		},
+		model.Run{
+			Cmd: model.Cmd{Argv: []string{"gofmt", "-w", "$CHANGED_FILES", "--files=$CHANGED_FILES"}},
+		},
+	}
+
+	pathMappings := []PathMapping{
+		PathMapping{
+			LocalPath:     AbsPath("test", "protos", "a.proto"),
+			ContainerPath: "/src/protos/a.proto",
+		},
+		PathMapping{
+			LocalPath:     AbsPath("test", "protos", "my file.proto"),
+			ContainerPath: "/src/protos/my file.proto",
+		},
+		PathMapping{
+			LocalPath:     AbsPath("test", "main.go"),
+			ContainerPath: "/src/main.go",
+		},
+	}
+
+	expected := []model.Cmd{
+		model.ToUnixCmd("protoc /src/protos/a.proto '/src/protos/my file.proto' && echo /src/protos/a.proto '/src/protos/my file.proto'"),
+		model.Cmd{Argv: []string{
+			"gofmt", "-w", "/src/protos/a.proto", "/src/protos/my file.proto", "/src/main.go",
+			"--files=/src/protos/a.proto /src/protos/my file.proto /src/main.go",
+		}},
+	}
+
+	actual, err := BoilRuns(runs, pathMappings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestBoilRunsChangedFilesForEach(t *testing.T) {
+	wd := AbsPath("test")
+	runs := []model.Run{
+		model.Run{
+			Cmd:         model.ToUnixCmd("protoc $CHANGED_FILES"),
+			Triggers:    model.NewPathSet([]string{"protos"}, wd),
+			ForEachFile: true,
+		},
+	}
+
+	pathMappings := []PathMapping{
+		PathMapping{
+			LocalPath:     AbsPath("test", "protos", "a.proto"),
+			ContainerPath: "/src/protos/a.proto",
+		},
+		PathMapping{
+			LocalPath:     AbsPath("test", "main.go"),
+			ContainerPath: "/src/main.go",
+		},
+		PathMapping{
+			LocalPath:     AbsPath("test", "protos", "it's.proto"),
+			ContainerPath: "/src/protos/it's.proto",
+		},
+	}
+
+	expected := []model.Cmd{
+		model.ToUnixCmd("protoc /src/protos/a.proto"),
+		model.ToUnixCmd(`protoc '/src/protos/it'"'"'s.proto'`),
+	}
+
+	actual, err := BoilRuns(runs, pathMappings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestBoilRunsForEachNoFilesChanged(t *testing.T) {
+	runs := []model.Run{
+		model.Run{
+			Cmd:         model.ToUnixCmd("echo $CHANGED_FILES"),
+			ForEachFile: true,
+		},
+	}
+
+	actual, err := BoilRuns(runs, []PathMapping{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Empty(t, actual)
+}
+
 func AbsPath(parts ...string) string {
 	if runtime.GOOS == "windows" {
 		return filepath.Join(append([]string{"C:\\home\\tilt"}, parts...)...)
@@ -86,3 +86,25 @@ func MaybeRunStepFailure(err error) (RunStepFailure, bool) {
 }
 
 var _ error = RunStepFailure{}
+
+// Indicates that the update failed because we couldn't write the tar archive
+// of local files -- as opposed to a failure unpacking it in the container.
+// These are usually transient local I/O problems, so it's safe to retry.
+type TarWriteError struct {
+	Err error
+}
+
+func (e TarWriteError) Error() string {
+	return fmt.Sprintf("writing tar archive: %v", e.Err)
+}
+
+func (e TarWriteError) Unwrap() error {
+	return e.Err
+}
+
+func IsTarWriteError(err error) bool {
+	var twe TarWriteError
+	return errors.As(err, &twe)
+}
+
+var _ error = TarWriteError{}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -111,31 +112,63 @@ func FilesToPathMappings(files []string, syncs []model.Sync) ([]PathMapping, []s
 }
 
 func fileToPathMapping(file string, sync []model.Sync) (pm PathMapping, couldMap bool, err error) {
-	for _, s := range sync {
+	i, relPath := syncIndexForFile(file, sync)
+	if i == -1 {
+		// The file doesn't match any sync src's.
+		return PathMapping{}, false, nil
+	}
+
+	s := sync[i]
+	localPathIsFile, err := isFile(s.LocalPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return PathMapping{}, false, fmt.Errorf("error stat'ing: %v", err)
+		}
+		// The sync's local path was deleted, so we can't tell if it was a file
+		// or a directory. Assume a file, so that deleting it never removes the
+		// whole directory it was synced into.
+		localPathIsFile = relPath == "."
+	}
+	var containerPath string
+	if endsWithUnixSeparator(s.ContainerPath) && localPathIsFile {
+		fileName := filepath.Base(s.LocalPath)
+		containerPath = path.Join(s.ContainerPath, fileName)
+	} else {
+		containerPath = path.Join(s.ContainerPath, filepath.ToSlash(relPath))
+	}
+	return PathMapping{
+		LocalPath:     file,
+		ContainerPath: containerPath,
+	}, true, nil
+}
+
+// Returns the index of the first sync that claims this file (and the file's path
+// relative to that sync), or -1 if no sync claims it.
+func syncIndexForFile(file string, sync []model.Sync) (int, string) {
+	for i, s := range sync {
 		// Open Q: can you sync files inside of syncs?! o_0
 		// TODO(maia): are symlinks etc. gonna kick our asses here? If so, will
 		// need ospath.RealChild -- but then can't deal with deleted local files.
 		relPath, isChild := ospath.Child(s.LocalPath, file)
 		if isChild {
-			localPathIsFile, err := isFile(s.LocalPath)
-			if err != nil {
-				return PathMapping{}, false, fmt.Errorf("error stat'ing: %v", err)
-			}
-			var containerPath string
-			if endsWithUnixSeparator(s.ContainerPath) && localPathIsFile {
-				fileName := filepath.Base(s.LocalPath)
-				containerPath = path.Join(s.ContainerPath, fileName)
-			} else {
-				containerPath = path.Join(s.ContainerPath, filepath.ToSlash(relPath))
-			}
-			return PathMapping{
-				LocalPath:     file,
-				ContainerPath: containerPath,
-			}, true, nil
+			return i, relPath
 		}
 	}
-	// The file doesn't match any sync src's.
-	return PathMapping{}, false, nil
+	return -1, ""
+}
+
+// SyncIndexesForPathMappings maps the LocalPath of each PathMapping to the index
+// of the sync that claimed it. Useful for explaining which sync a file went through
+// when syncs overlap. PathMappings that don't match any sync are omitted.
+func SyncIndexesForPathMappings(pms []PathMapping, syncs []model.Sync) map[string]int {
+	result := make(map[string]int, len(pms))
+	for _, pm := range pms {
+		i, _ := syncIndexForFile(pm.LocalPath, syncs)
+		if i != -1 {
+			result[pm.LocalPath] = i
+		}
+	}
+	return result
 }
 
 func endsWithUnixSeparator(path string) bool {
@@ -180,10 +213,132 @@ func MissingLocalPaths(ctx context.Context, mappings []PathMapping) (missing, re
 	return missing, rest, nil
 }
 
+// A container path that more than one local file maps to.
+type PathMappingCollision struct {
+	ContainerPath string
+	LocalPaths    []string
+}
+
+func (c PathMappingCollision) String() string {
+	return fmt.Sprintf("'%s' (matched local paths: '%s')", c.ContainerPath, strings.Join(c.LocalPaths, "', '"))
+}
+
+// CollidingContainerPaths returns the container paths that more than one of the
+// given local files would be copied to, sorted by container path. When this
+// happens, whichever file is copied last silently wins.
+func CollidingContainerPaths(mappings []PathMapping) []PathMappingCollision {
+	localPaths := make(map[string][]string)
+	for _, m := range mappings {
+		localPaths[m.ContainerPath] = append(localPaths[m.ContainerPath], m.LocalPath)
+	}
+
+	var result []PathMappingCollision
+	for containerPath, locals := range localPaths {
+		if len(locals) < 2 {
+			continue
+		}
+		sort.Strings(locals)
+		result = append(result, PathMappingCollision{ContainerPath: containerPath, LocalPaths: locals})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ContainerPath < result[j].ContainerPath
+	})
+	return result
+}
+
+// SplitSharedRemovals finds the container paths we shouldn't remove, because
+// another sync still has local files that live there. For example, if both
+// ./src/assets and ./gen/assets sync to /app/assets, deleting ./src/assets
+// shouldn't `rm -rf` the generated files out of /app/assets.
+func SplitSharedRemovals(toRemove []PathMapping, syncs []model.Sync) (safe, shared []PathMapping, err error) {
+	for _, pm := range toRemove {
+		isShared, err := containerPathHasOtherSources(pm, syncs)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "SplitSharedRemovals")
+		}
+		if isShared {
+			shared = append(shared, pm)
+		} else {
+			safe = append(safe, pm)
+		}
+	}
+	return safe, shared, nil
+}
+
+func containerPathHasOtherSources(pm PathMapping, syncs []model.Sync) (bool, error) {
+	for _, s := range syncs {
+		syncContainerPath, err := syncDestination(s)
+		if err != nil {
+			return false, err
+		}
+
+		// The whole sync lives under the path we'd remove.
+		if containerPathIsChild(pm.ContainerPath, syncContainerPath) {
+			exists, err := localPathExists(s.LocalPath)
+			if err != nil || exists {
+				return exists, err
+			}
+			continue
+		}
+
+		// The path we'd remove lives under the sync, so check if the sync has
+		// its own copy of it.
+		if containerPathIsChild(syncContainerPath, pm.ContainerPath) {
+			rel := strings.TrimPrefix(pm.ContainerPath, syncContainerPath)
+			exists, err := localPathExists(filepath.Join(s.LocalPath, filepath.FromSlash(rel)))
+			if err != nil || exists {
+				return exists, err
+			}
+		}
+	}
+	return false, nil
+}
+
+// Where a sync puts its files in the container. Syncing a file to a
+// directory (i.e., a dest ending in /) puts it inside that directory.
+func syncDestination(s model.Sync) (string, error) {
+	if endsWithUnixSeparator(s.ContainerPath) {
+		localPathIsFile, err := isFile(s.LocalPath)
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+		if localPathIsFile {
+			return path.Join(s.ContainerPath, filepath.Base(s.LocalPath)), nil
+		}
+	}
+	return path.Clean(s.ContainerPath), nil
+}
+
+// True if child is parent or inside it.
+func containerPathIsChild(parent, child string) bool {
+	parent = path.Clean(parent)
+	child = path.Clean(child)
+	if parent == "/" || parent == child {
+		return true
+	}
+	return strings.HasPrefix(child, parent+"/")
+}
+
+func localPathExists(p string) (bool, error) {
+	_, err := os.Stat(p)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+// PathMappingsToContainerPaths returns the container paths in the form the
+// container's OS expects, i.e., with backslashes in a Windows container.
 func PathMappingsToContainerPaths(mappings []PathMapping) []string {
 	res := make([]string, len(mappings))
 	for i, m := range mappings {
 		res[i] = m.ContainerPath
+		if model.IsWindowsContainerPath(m.ContainerPath) {
+			res[i] = strings.ReplaceAll(m.ContainerPath, "/", `\`)
+		}
 	}
 	return res
 }
@@ -124,3 +124,133 @@ func TestFileNotInSyncYieldsNoMapping(t *testing.T) {
 	assert.Empty(t, actual, "expected no path mapping returned for a file not matching any syncs")
 	assert.Equal(t, files, skipped)
 }
+
+func TestSyncIndexesForOverlappingSyncs(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	syncs := []model.Sync{
+		model.Sync{
+			LocalPath:     f.JoinPath("src", "static"),
+			ContainerPath: "/static",
+		},
+		model.Sync{
+			LocalPath:     f.JoinPath("src"),
+			ContainerPath: "/app",
+		},
+	}
+
+	pms := []PathMapping{
+		PathMapping{LocalPath: f.JoinPath("src", "static", "index.html"), ContainerPath: "/static/index.html"},
+		PathMapping{LocalPath: f.JoinPath("src", "main.go"), ContainerPath: "/app/main.go"},
+		PathMapping{LocalPath: f.JoinPath("elsewhere", "README"), ContainerPath: "/README"},
+	}
+
+	expected := map[string]int{
+		f.JoinPath("src", "static", "index.html"): 0,
+		f.JoinPath("src", "main.go"):              1,
+	}
+	assert.Equal(t, expected, SyncIndexesForPathMappings(pms, syncs))
+}
+
+func TestCollidingContainerPaths(t *testing.T) {
+	pms := []PathMapping{
+		PathMapping{LocalPath: "/src/foo/x.txt", ContainerPath: "/app/x.txt"},
+		PathMapping{LocalPath: "/src/main.go", ContainerPath: "/app/main.go"},
+		PathMapping{LocalPath: "/src/bar/x.txt", ContainerPath: "/app/x.txt"},
+	}
+
+	expected := []PathMappingCollision{
+		{ContainerPath: "/app/x.txt", LocalPaths: []string{"/src/bar/x.txt", "/src/foo/x.txt"}},
+	}
+	assert.Equal(t, expected, CollidingContainerPaths(pms))
+	assert.Empty(t, CollidingContainerPaths(pms[:2]))
+}
+
+func TestSplitSharedRemovals(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	f.TouchFiles([]string{
+		filepath.Join("gen", "assets", "bundle.js"),
+		filepath.Join("vendor", "lib.go"),
+	})
+
+	syncs := []model.Sync{
+		model.Sync{LocalPath: f.JoinPath("src", "assets"), ContainerPath: "/app/assets"},
+		model.Sync{LocalPath: f.JoinPath("gen", "assets"), ContainerPath: "/app/assets"},
+		model.Sync{LocalPath: f.JoinPath("src", "pkg"), ContainerPath: "/app/pkg"},
+		model.Sync{LocalPath: f.JoinPath("vendor"), ContainerPath: "/app/pkg/vendor"},
+		model.Sync{LocalPath: f.JoinPath("src", "old"), ContainerPath: "/app/old"},
+	}
+
+	toRemove := []PathMapping{
+		// gen/assets still has files in /app/assets.
+		PathMapping{LocalPath: f.JoinPath("src", "assets"), ContainerPath: "/app/assets"},
+		// ...but not a file with this name.
+		PathMapping{LocalPath: f.JoinPath("src", "assets", "old.js"), ContainerPath: "/app/assets/old.js"},
+		// vendor still syncs into a dir under /app/pkg.
+		PathMapping{LocalPath: f.JoinPath("src", "pkg"), ContainerPath: "/app/pkg"},
+		// Nothing else lives under /app/old, so it's safe to remove recursively.
+		PathMapping{LocalPath: f.JoinPath("src", "old"), ContainerPath: "/app/old"},
+	}
+
+	safe, shared, err := SplitSharedRemovals(toRemove, syncs)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []PathMapping{toRemove[1], toRemove[3]}, safe)
+		assert.Equal(t, []PathMapping{toRemove[0], toRemove[2]}, shared)
+	}
+}
+
+func TestDeletedSyncRootMapsToItsContainerPath(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	syncs := []model.Sync{
+		model.Sync{LocalPath: f.JoinPath("assets"), ContainerPath: "/app/assets"},
+		model.Sync{LocalPath: f.JoinPath("config.yaml"), ContainerPath: "/etc/app/"},
+	}
+
+	actual, _, err := FilesToPathMappings([]string{f.JoinPath("assets"), f.JoinPath("config.yaml")}, syncs)
+	if assert.NoError(t, err) {
+		expected := []PathMapping{
+			PathMapping{LocalPath: f.JoinPath("assets"), ContainerPath: "/app/assets"},
+			PathMapping{LocalPath: f.JoinPath("config.yaml"), ContainerPath: "/etc/app/config.yaml"},
+		}
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestFilesToPathMappingsWindowsContainer(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	f.TouchFiles([]string{filepath.Join("src", "child", "fileA"), "config.yaml"})
+
+	syncs := []model.Sync{
+		model.Sync{LocalPath: f.JoinPath("src"), ContainerPath: "C:/app"},
+		model.Sync{LocalPath: f.JoinPath("config.yaml"), ContainerPath: "C:/etc/"},
+	}
+
+	actual, _, err := FilesToPathMappings([]string{
+		f.JoinPath("src", "child", "fileA"),
+		f.JoinPath("config.yaml"),
+	}, syncs)
+	if assert.NoError(t, err) {
+		// Local paths are always converted to forward slashes, regardless of
+		// the local OS.
+		expected := []PathMapping{
+			PathMapping{LocalPath: f.JoinPath("src", "child", "fileA"), ContainerPath: "C:/app/child/fileA"},
+			PathMapping{LocalPath: f.JoinPath("config.yaml"), ContainerPath: "C:/etc/config.yaml"},
+		}
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestPathMappingsToContainerPaths(t *testing.T) {
+	pms := []PathMapping{
+		PathMapping{LocalPath: "/src/a", ContainerPath: "/app/a"},
+		PathMapping{LocalPath: "/src/b", ContainerPath: "C:/app/b"},
+	}
+	assert.Equal(t, []string{"/app/a", `C:\app\b`}, PathMappingsToContainerPaths(pms))
+}
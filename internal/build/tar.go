@@ -2,6 +2,7 @@ package build
 
 import (
 	"archive/tar"
+	"compress/gzip"
 	"context"
 	"io"
 	"os"
@@ -133,7 +134,7 @@ func (a *ArchiveBuilder) entriesForPath(ctx context.Context, localPath, containe
 		}
 	}
 
-	containerPath = strings.TrimPrefix(containerPath, "/")
+	containerPath = strings.TrimPrefix(containerPathFromRoot(containerPath), "/")
 
 	result := make([]archiveEntry, 0)
 	err = filepath.Walk(localPath, func(curLocalPath string, info os.FileInfo, err error) error {
@@ -207,6 +208,15 @@ func (a *ArchiveBuilder) entriesForPath(ctx context.Context, localPath, containe
 	return result, nil
 }
 
+// We extract archives at the root of the container's filesystem, so
+// entries for a Windows container drop the drive (e.g., C:/app -> /app).
+func containerPathFromRoot(containerPath string) string {
+	if model.IsWindowsContainerPath(containerPath) {
+		return model.NormalizeContainerPath(containerPath)[2:]
+	}
+	return containerPath
+}
+
 func (a *ArchiveBuilder) writeEntry(entry archiveEntry) error {
 	path := entry.path
 	header := entry.header
@@ -282,17 +292,34 @@ func TarPath(ctx context.Context, writer io.Writer, path string) error {
 
 func TarArchiveForPaths(ctx context.Context, toArchive []PathMapping, filter model.PathMatcher) io.Reader {
 	pr, pw := io.Pipe()
-	go tarArchiveForPaths(ctx, pw, toArchive, filter)
+	go tarArchiveForPaths(ctx, pw, toArchive, filter, false)
+	return pr
+}
+
+// GzipTarArchiveForPaths is like TarArchiveForPaths, but gzips the archive.
+func GzipTarArchiveForPaths(ctx context.Context, toArchive []PathMapping, filter model.PathMatcher) io.Reader {
+	pr, pw := io.Pipe()
+	go tarArchiveForPaths(ctx, pw, toArchive, filter, true)
 	return pr
 }
 
-func tarArchiveForPaths(ctx context.Context, pw *io.PipeWriter, toArchive []PathMapping, filter model.PathMatcher) {
-	ab := NewArchiveBuilder(pw, filter)
+func tarArchiveForPaths(ctx context.Context, pw *io.PipeWriter, toArchive []PathMapping, filter model.PathMatcher, gzipped bool) {
+	var w io.Writer = pw
+	var gz *gzip.Writer
+	if gzipped {
+		gz = gzip.NewWriter(pw)
+		w = gz
+	}
+
+	ab := NewArchiveBuilder(w, filter)
 	err := ab.ArchivePathsIfExist(ctx, toArchive)
 	if err != nil {
-		_ = pw.CloseWithError(errors.Wrap(err, "archivePathsIfExists"))
+		_ = pw.CloseWithError(TarWriteError{Err: errors.Wrap(err, "archivePathsIfExists")})
 	} else {
 		_ = ab.Close()
+		if gz != nil {
+			_ = gz.Close()
+		}
 		_ = pw.Close()
 	}
 }
@@ -3,9 +3,13 @@ package build
 import (
 	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"io"
 	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"runtime"
 	"testing"
 
@@ -77,6 +81,86 @@ func TestArchivePathsIfExists(t *testing.T) {
 	})
 }
 
+func TestGzipTarArchiveForPaths(t *testing.T) {
+	f := newFixture(t)
+	defer f.tearDown()
+
+	f.WriteFile("a", "a")
+	f.WriteFile("dir/b", "b")
+
+	paths := []PathMapping{
+		PathMapping{LocalPath: f.JoinPath("a"), ContainerPath: "/a"},
+		PathMapping{LocalPath: f.JoinPath("dir"), ContainerPath: "/dir"},
+	}
+
+	gz, err := gzip.NewReader(GzipTarArchiveForPaths(f.ctx, paths, model.EmptyMatcher))
+	require.NoError(t, err)
+
+	actual := tar.NewReader(gz)
+	f.assertFilesInTar(actual, []expectedFile{
+		expectedFile{Path: "a", Contents: "a"},
+		expectedFile{Path: "dir/b", Contents: "b"},
+	})
+}
+
+func TestArchiveWindowsContainerPaths(t *testing.T) {
+	f := newFixture(t)
+	defer f.tearDown()
+
+	f.WriteFile("a", "a")
+	f.WriteFile("dir/b", "b")
+
+	paths := []PathMapping{
+		PathMapping{LocalPath: f.JoinPath("a"), ContainerPath: "C:/app/a"},
+		PathMapping{LocalPath: f.JoinPath("dir"), ContainerPath: "C:/app/dir"},
+	}
+
+	// Entries are relative to the root of the drive.
+	actual := tar.NewReader(TarArchiveForPaths(f.ctx, paths, model.EmptyMatcher))
+	f.assertFilesInTar(actual, []expectedFile{
+		expectedFile{Path: "app/a", Contents: "a"},
+		expectedFile{Path: "app/dir/b", Contents: "b"},
+	})
+}
+
+func TestArchiveKeepsExecutableBit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows has no executable bit to keep")
+	}
+
+	f := newFixture(t)
+	defer f.tearDown()
+
+	f.WriteFile("src/run.sh", "#!/bin/sh\necho hi\n")
+	require.NoError(t, os.Chmod(f.JoinPath("src", "run.sh"), 0755))
+
+	paths := []PathMapping{
+		PathMapping{LocalPath: f.JoinPath("src"), ContainerPath: "/app"},
+	}
+	contents, err := io.ReadAll(TarArchiveForPaths(f.ctx, paths, model.EmptyMatcher))
+	require.NoError(t, err)
+
+	f.assertFilesInTar(tar.NewReader(bytes.NewReader(contents)), []expectedFile{
+		expectedFile{Path: "app/run.sh", Contents: "#!/bin/sh\necho hi\n", Mode: 0755},
+	})
+
+	// Unpack it the way we do in the container, and make sure the script is still executable.
+	tarBin, err := exec.LookPath("tar")
+	if err != nil {
+		t.Skip("no tar binary to unpack with")
+	}
+	dest := f.JoinPath("dest")
+	f.MkdirAll(dest)
+	cmd := exec.Command(tarBin, "-C", dest, "-x", "-f", "-")
+	cmd.Stdin = bytes.NewReader(contents)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "tar: %s", out)
+
+	info, err := os.Stat(filepath.Join(dest, "app", "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0111), info.Mode()&0111, "expected run.sh to stay executable, got %s", info.Mode())
+}
+
 func TestDontArchiveTiltfile(t *testing.T) {
 	f := newFixture(t)
 	defer f.tearDown()
@@ -16,6 +16,7 @@ import (
 
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/output"
+	tiltfileprint "github.com/tilt-dev/tilt/internal/tiltfile/print"
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
@@ -158,6 +159,9 @@ func addCommand(parent *cobra.Command, child tiltCmd) {
 			if printErr != nil {
 				panic(printErr)
 			}
+			if code, ok := tiltfileprint.ExitCode(err); ok {
+				os.Exit(code)
+			}
 			os.Exit(1)
 		}
 	}
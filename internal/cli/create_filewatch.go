@@ -22,7 +22,8 @@ type createFileWatchCmd struct {
 	helper *createHelper
 	cmd    *cobra.Command
 
-	ignoreValues []string
+	ignoreValues      []string
+	ignoreRegexValues []string
 }
 
 var _ tiltCmd = &createFileWatchCmd{}
@@ -61,6 +62,8 @@ trigger events when a file changes.
 
 	cmd.Flags().StringSliceVar(&c.ignoreValues, "ignore", nil,
 		"Patterns to ignore. Supports same syntax as .dockerignore. Paths are relative to the current directory.")
+	cmd.Flags().StringSliceVar(&c.ignoreRegexValues, "ignore-regex", nil,
+		"Regular expressions to ignore. Matched against paths relative to the current directory.")
 
 	c.helper.addFlags(cmd)
 	c.cmd = cmd
@@ -140,11 +143,16 @@ func (c *createFileWatchCmd) ignores() ([]v1alpha1.IgnoreDef, error) {
 		return nil, err
 	}
 
-	if len(c.ignoreValues) == 0 {
+	if len(c.ignoreValues) == 0 && len(c.ignoreRegexValues) == 0 {
 		return nil, nil
 	}
 
 	result.BasePath = cwd
-	result.Patterns = append([]string{}, c.ignoreValues...)
+	if len(c.ignoreValues) != 0 {
+		result.Patterns = append([]string{}, c.ignoreValues...)
+	}
+	if len(c.ignoreRegexValues) != 0 {
+		result.Regexes = append([]string{}, c.ignoreRegexValues...)
+	}
 	return []v1alpha1.IgnoreDef{result}, nil
 }
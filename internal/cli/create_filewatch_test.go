@@ -70,3 +70,47 @@ func TestCreateFileWatchNoIgnore(t *testing.T) {
 	}, fw.Spec.WatchedPaths)
 	assert.Equal(t, 0, len(fw.Spec.Ignores))
 }
+
+func TestCreateFileWatchIgnoreRegex(t *testing.T) {
+	f := newServerFixture(t)
+	defer f.TearDown()
+
+	out := bytes.NewBuffer(nil)
+
+	cmd := newCreateFileWatchCmd()
+	cmd.helper.streams.Out = out
+	c := cmd.register()
+	err := c.Flags().Parse([]string{
+		"--ignore-regex", `_test\.go$`,
+	})
+	require.NoError(t, err)
+
+	err = cmd.run(f.ctx, []string{"my-watch", "src"})
+	require.NoError(t, err)
+
+	var fw v1alpha1.FileWatch
+	err = f.client.Get(f.ctx, types.NamespacedName{Name: "my-watch"}, &fw)
+	require.NoError(t, err)
+
+	cwd, _ := os.Getwd()
+	assert.Equal(t, cwd, fw.Spec.Ignores[0].BasePath)
+	assert.Equal(t, []string{`_test\.go$`}, fw.Spec.Ignores[0].Regexes)
+	assert.Empty(t, fw.Spec.Ignores[0].Patterns)
+}
+
+func TestCreateFileWatchInvalidIgnoreRegex(t *testing.T) {
+	f := newServerFixture(t)
+	defer f.TearDown()
+
+	cmd := newCreateFileWatchCmd()
+	cmd.helper.streams.Out = bytes.NewBuffer(nil)
+	c := cmd.register()
+	err := c.Flags().Parse([]string{
+		"--ignore-regex", `gen_(`,
+	})
+	require.NoError(t, err)
+
+	err = cmd.run(f.ctx, []string{"my-watch", "src"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec.ignores[0].regexes[0]")
+}
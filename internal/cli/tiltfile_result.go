@@ -14,6 +14,7 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/tiltfile"
+	tiltfileprint "github.com/tilt-dev/tilt/internal/tiltfile/print"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -60,6 +61,7 @@ By default, prints Tiltfile execution results as JSON (note: the API is unstable
 Exit code 0: successful Tiltfile evaluation (data printed to stdout)
 Exit code 1: some failure in setup, printing results, etc. (any logs printed to stderr)
 Exit code 5: error when evaluating the Tiltfile, such as syntax error, illegal Tiltfile operation, etc. (any logs printed to stderr)
+Other exit codes: the Tiltfile called fail() with a code, e.g. fail('bad config', code=2)
 
 Run with -v | --verbose to print Tiltfile execution logs on stderr, regardless of whether there was an error.`,
 	}
@@ -103,6 +105,9 @@ func (c *tiltfileResultCmd) run(ctx context.Context, args []string) error {
 		// to STDERR and use the exit code to indicate that it's an error
 		// from Tiltfile parsing.
 		fmt.Fprintln(os.Stderr, tlr.Error)
+		if code, ok := tiltfileprint.ExitCode(tlr.Error); ok {
+			os.Exit(code)
+		}
 		os.Exit(TiltfileErrExitCode)
 	}
 
@@ -2,6 +2,7 @@ package containerupdate
 
 import (
 	"context"
+	"fmt"
 	"io"
 
 	"github.com/tilt-dev/tilt/internal/store"
@@ -9,6 +10,21 @@ import (
 )
 
 type ContainerUpdater interface {
+	// If we couldn't write the archive locally, the returned error
+	// is a build.TarWriteError (as opposed to a failure in the container).
 	UpdateContainer(ctx context.Context, cInfo store.ContainerInfo,
 		archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error
 }
+
+// Optional for a ContainerUpdater: send a signal to the main process (PID 1)
+// of a container, so that apps that reload on a signal pick up the synced
+// files without a container restart.
+type ProcessSignaler interface {
+	// The signal is a name without the SIG prefix (e.g., "HUP").
+	SignalProcess(ctx context.Context, cInfo store.ContainerInfo, signal string) error
+}
+
+// Use the shell's built-in kill, so that the container doesn't need procps.
+func signalArgv(signal string) []string {
+	return []string{"sh", "-c", fmt.Sprintf("kill -s %s 1", signal)}
+}
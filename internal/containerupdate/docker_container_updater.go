@@ -22,6 +22,7 @@ type DockerUpdater struct {
 }
 
 var _ ContainerUpdater = &DockerUpdater{}
+var _ ProcessSignaler = &DockerUpdater{}
 
 func NewDockerUpdater(dCli docker.Client) *DockerUpdater {
 	return &DockerUpdater{dCli: dCli}
@@ -46,11 +47,17 @@ func (cu *DockerUpdater) UpdateContainer(ctx context.Context, cInfo store.Contai
 	// (whereas the Exec API is part of the CRI and much more battle-tested).
 	// Discussion:
 	// https://github.com/tilt-dev/tilt/issues/3708
+	archive := newArchiveReader(archiveToCopy)
+	buf := bytes.NewBuffer(nil)
+	tarWriter := io.MultiWriter(l.Writer(logger.InfoLvl), buf)
 	err = cu.dCli.ExecInContainer(ctx, cInfo.ContainerID, model.Cmd{
-		Argv: tarArgv(),
-	}, archiveToCopy, l.Writer(logger.InfoLvl))
+		Argv: tarArgv(archive.gzipped),
+	}, archive.stdin(), tarWriter)
 	if err != nil {
-		return errors.Wrap(err, "copying files")
+		if isReadOnlyFilesystemError(buf, err) {
+			err = readOnlyFilesystemError(err)
+		}
+		return archive.copyErr(buf, errors.Wrap(err, "copying files"))
 	}
 
 	// Exec run's on container
@@ -75,6 +82,18 @@ func (cu *DockerUpdater) UpdateContainer(ctx context.Context, cInfo store.Contai
 	return nil
 }
 
+func (cu *DockerUpdater) SignalProcess(ctx context.Context, cInfo store.ContainerInfo, signal string) error {
+	out := bytes.NewBuffer(nil)
+	err := cu.dCli.ExecInContainer(ctx, cInfo.ContainerID, model.Cmd{Argv: signalArgv(signal)}, nil, out)
+	if err != nil {
+		if docker.IsExitError(err) {
+			return fmt.Errorf("Error sending SIG%s to main process: %s", signal, out.String())
+		}
+		return errors.Wrapf(err, "Error sending SIG%s to main process", signal)
+	}
+	return nil
+}
+
 func (cu *DockerUpdater) rmPathsFromContainer(ctx context.Context, cID container.ID, paths []string) error {
 	if len(paths) == 0 {
 		return nil
@@ -87,6 +87,21 @@ func TestUpdateContainerHotReloadDoesNotRestartContainer(t *testing.T) {
 	assert.Equal(f.t, 0, len(f.dCli.RestartsByContainer))
 }
 
+func TestSignalProcessInDockerContainer(t *testing.T) {
+	f := newDCUFixture(t)
+
+	err := f.dcu.SignalProcess(f.ctx, TestContainerInfo, "USR1")
+	if err != nil {
+		f.t.Fatal(err)
+	}
+
+	expectedExecs := []docker.ExecCall{
+		docker.ExecCall{Container: docker.TestContainer, Cmd: model.Cmd{Argv: []string{"sh", "-c", "kill -s USR1 1"}}},
+	}
+	assert.Equal(f.t, expectedExecs, f.dCli.ExecCalls)
+	assert.Equal(f.t, 0, f.dCli.RestartsByContainer[docker.TestContainer])
+}
+
 func TestUpdateContainerKillTask(t *testing.T) {
 	f := newDCUFixture(t)
 
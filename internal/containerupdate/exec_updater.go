@@ -7,6 +7,8 @@ import (
 	"io"
 	"strings"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -19,6 +21,7 @@ type ExecUpdater struct {
 }
 
 var _ ContainerUpdater = &ExecUpdater{}
+var _ ProcessSignaler = &ExecUpdater{}
 
 func NewExecUpdater(kCli k8s.Client) *ExecUpdater {
 	return &ExecUpdater{kCli: kCli}
@@ -50,10 +53,11 @@ func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo store.Containe
 	// copy files to container
 	buf := bytes.NewBuffer(nil)
 	tarWriter := io.MultiWriter(w, buf)
+	archive := newArchiveReader(archiveToCopy)
 	err := cu.kCli.Exec(ctx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
-		tarArgv(), archiveToCopy, tarWriter, tarWriter)
+		tarArgv(archive.gzipped), archive.stdin(), tarWriter, tarWriter)
 	if err != nil {
-		return fmt.Errorf("copying changed files: %v", handleK8sExecError(buf, err))
+		return archive.copyErr(buf, fmt.Errorf("copying changed files: %v", handleK8sExecError(buf, err)))
 	}
 
 	// run commands
@@ -70,7 +74,26 @@ func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo store.Containe
 	return nil
 }
 
+func (cu *ExecUpdater) SignalProcess(ctx context.Context, cInfo store.ContainerInfo, signal string) error {
+	buf := bytes.NewBuffer(nil)
+	w := io.MultiWriter(logger.Get(ctx).Writer(logger.InfoLvl), buf)
+	err := cu.kCli.Exec(ctx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
+		signalArgv(signal), nil, w, w)
+	if err != nil {
+		return fmt.Errorf("sending SIG%s to main process: %v", signal, handleK8sExecError(buf, err))
+	}
+	return nil
+}
+
 func handleK8sExecError(out *bytes.Buffer, err error) error {
+	if isExecForbiddenError(err) {
+		return execForbiddenError(err)
+	}
+
+	if isReadOnlyFilesystemError(out, err) {
+		return readOnlyFilesystemError(err)
+	}
+
 	msg := strings.ToLower(fmt.Sprintf("%s\n%s", out.String(), err.Error()))
 	if strings.Contains(msg, "permission denied") || strings.Contains(msg, "cannot open") {
 		return fmt.Errorf("%v\n"+
@@ -82,3 +105,22 @@ func handleK8sExecError(out *bytes.Buffer, err error) error {
 	}
 	return err
 }
+
+// Locked-down clusters often don't let users exec into pods at all. The API server
+// rejects the exec before anything runs in the container.
+func isExecForbiddenError(err error) bool {
+	if apierrors.IsForbidden(err) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "forbidden") && strings.Contains(msg, "pods/exec")
+}
+
+func execForbiddenError(err error) error {
+	return fmt.Errorf("%v\n"+
+		"This cluster doesn't allow you to exec into pods (pods/exec is forbidden), "+
+		"so Tilt can't copy files into the running container. Please check:\n"+
+		"  1) That your user has the `create` permission on `pods/exec` in this namespace\n"+
+		"  2) Or, run Tilt with `--update-mode=image` to rebuild and redeploy images instead of live updating",
+		err)
+}
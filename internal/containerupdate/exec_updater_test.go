@@ -2,6 +2,7 @@ package containerupdate
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
@@ -9,6 +10,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/util/exec"
 
 	"github.com/tilt-dev/tilt/internal/build"
@@ -131,6 +135,129 @@ func TestUpdateContainerPermissionDenied(t *testing.T) {
 	assert.Equal(t, 1, len(f.kCli.ExecCalls))
 }
 
+func TestUpdateContainerReadOnlyFilesystem(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecOutputs = []io.Reader{strings.NewReader("tar: app/index.js: Cannot open: Read-only file system\n")}
+	f.kCli.ExecErrors = []error{exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 2"), Code: 2}}
+
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), nil, cmds, true)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "read-only root filesystem")
+		assert.NotContains(t, err.Error(), "container filesystem denied access")
+	}
+	assert.Equal(t, 1, len(f.kCli.ExecCalls))
+}
+
+func TestUpdateContainerExecForbidden(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecErrors = []error{apierrors.NewForbidden(
+		schema.GroupResource{Resource: "pods/exec"}, "my-pod", fmt.Errorf("RBAC: access denied"))}
+
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), nil, cmds, true)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "pods/exec is forbidden")
+		assert.Contains(t, err.Error(), "--update-mode=image")
+		assert.NotContains(t, err.Error(), "container filesystem denied access")
+	}
+	assert.Equal(t, 1, len(f.kCli.ExecCalls))
+}
+
+func TestUpdateContainerExecForbiddenMessage(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecErrors = []error{fmt.Errorf(`pods "my-pod" is forbidden: ` +
+		`User "dev" cannot create resource "pods/exec" in API group "" in the namespace "default"`)}
+
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), toDelete, cmds, true)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "pods/exec is forbidden")
+	}
+}
+
+func TestSignalProcess(t *testing.T) {
+	f := newExecFixture(t)
+
+	err := f.ecu.SignalProcess(f.ctx, TestContainerInfo, "HUP")
+	require.NoError(t, err)
+
+	if assert.Len(t, f.kCli.ExecCalls, 1) {
+		assert.Equal(t, []string{"sh", "-c", "kill -s HUP 1"}, f.kCli.ExecCalls[0].Cmd)
+		assert.Equal(t, TestContainerInfo.PodID, f.kCli.ExecCalls[0].PID)
+	}
+}
+
+func TestSignalProcessExecForbidden(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecErrors = []error{apierrors.NewForbidden(
+		schema.GroupResource{Resource: "pods/exec"}, "my-pod", fmt.Errorf("RBAC: access denied"))}
+
+	err := f.ecu.SignalProcess(f.ctx, TestContainerInfo, "HUP")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "sending SIGHUP")
+		assert.Contains(t, err.Error(), "pods/exec is forbidden")
+	}
+}
+
+func TestUpdateContainerGzippedArchive(t *testing.T) {
+	f := newExecFixture(t)
+
+	archive := gzipped(t, "hello world")
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, bytes.NewReader(archive), nil, nil, true)
+	if assert.NoError(t, err) && assert.Len(t, f.kCli.ExecCalls, 1) {
+		call := f.kCli.ExecCalls[0]
+		assert.Equal(t, []string{"tar", "-C", "/", "-x", "-z", "-f", "-"}, call.Cmd)
+		assert.Equal(t, archive, call.Stdin)
+	}
+}
+
+func TestUpdateContainerGzipUnsupported(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecOutputs = []io.Reader{strings.NewReader("tar: invalid option -- 'z'\n")}
+	f.kCli.ExecErrors = []error{exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 1"), Code: 1}}
+
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, bytes.NewReader(gzipped(t, "hello world")), nil, cmds, true)
+	if assert.Error(t, err) {
+		assert.True(t, IsGzipUnsupportedError(err))
+	}
+}
+
+func TestUpdateContainerUncompressedInvalidOptionIsNotGzipUnsupported(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecOutputs = []io.Reader{strings.NewReader("tar: invalid option -- 'C'\n")}
+	f.kCli.ExecErrors = []error{exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 1"), Code: 1}}
+
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), nil, cmds, true)
+	if assert.Error(t, err) {
+		assert.False(t, IsGzipUnsupportedError(err))
+	}
+}
+
+func TestUpdateContainerTarWriteError(t *testing.T) {
+	f := newExecFixture(t)
+
+	tarErr := build.TarWriteError{Err: fmt.Errorf("read foo.py: input/output error")}
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, errReader{err: tarErr}, nil, cmds, true)
+	if assert.Error(t, err) {
+		assert.True(t, build.IsTarWriteError(err))
+	}
+}
+
+func TestUpdateContainerUnpackErrorIsNotTarWriteError(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecErrors = []error{exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 2"), Code: 2}}
+
+	err := f.ecu.UpdateContainer(f.ctx, TestContainerInfo, newReader("hello world"), nil, cmds, true)
+	if assert.Error(t, err) {
+		assert.False(t, build.IsTarWriteError(err))
+	}
+}
+
 type execUpdaterFixture struct {
 	t    testing.TB
 	ctx  context.Context
@@ -153,6 +280,23 @@ func newExecFixture(t testing.TB) *execUpdaterFixture {
 	}
 }
 
+func gzipped(t testing.TB, contents string) []byte {
+	buf := bytes.NewBuffer(nil)
+	gz := gzip.NewWriter(buf)
+	_, err := gz.Write([]byte(contents))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
 func newReader(contents string) io.Reader {
 	return bytes.NewBuffer([]byte(contents))
 }
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
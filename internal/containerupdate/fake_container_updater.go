@@ -3,15 +3,21 @@ package containerupdate
 import (
 	"context"
 	"io"
+	"sync"
 
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type FakeContainerUpdater struct {
+	mu sync.Mutex
+
 	UpdateErrs []error
 
 	Calls []UpdateContainerCall
+
+	SignalErrs  []error
+	SignalCalls []SignalProcessCall
 }
 
 type UpdateContainerCall struct {
@@ -22,12 +28,22 @@ type UpdateContainerCall struct {
 	HotReload     bool
 }
 
+type SignalProcessCall struct {
+	ContainerInfo store.ContainerInfo
+	Signal        string
+}
+
 func (cu *FakeContainerUpdater) SetUpdateErr(err error) {
+	cu.mu.Lock()
+	defer cu.mu.Unlock()
 	cu.UpdateErrs = []error{err}
 }
 
 func (cu *FakeContainerUpdater) UpdateContainer(ctx context.Context, cInfo store.ContainerInfo,
 	archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error {
+	cu.mu.Lock()
+	defer cu.mu.Unlock()
+
 	cu.Calls = append(cu.Calls, UpdateContainerCall{
 		ContainerInfo: cInfo,
 		Archive:       archiveToCopy,
@@ -45,3 +61,23 @@ func (cu *FakeContainerUpdater) UpdateContainer(ctx context.Context, cInfo store
 	}
 	return err
 }
+
+func (cu *FakeContainerUpdater) SignalProcess(ctx context.Context, cInfo store.ContainerInfo, signal string) error {
+	cu.mu.Lock()
+	defer cu.mu.Unlock()
+
+	cu.SignalCalls = append(cu.SignalCalls, SignalProcessCall{
+		ContainerInfo: cInfo,
+		Signal:        signal,
+	})
+
+	var err error
+	if len(cu.SignalErrs) > 0 {
+		err = cu.SignalErrs[0]
+		cu.SignalErrs = append([]error{}, cu.SignalErrs[1:]...)
+	}
+	return err
+}
+
+var _ ContainerUpdater = &FakeContainerUpdater{}
+var _ ProcessSignaler = &FakeContainerUpdater{}
@@ -1,5 +1,132 @@
 package containerupdate
 
-func tarArgv() []string {
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/tilt-dev/tilt/internal/build"
+)
+
+// The first two bytes of every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+func tarArgv(gzipped bool) []string {
+	if gzipped {
+		return []string{"tar", "-C", "/", "-x", "-z", "-f", "-"}
+	}
 	return []string{"tar", "-C", "/", "-x", "-f", "-"}
 }
+
+// Returned when we sent a gzipped archive to a container that can't unpack it
+// (e.g., a minimal image whose `tar` wasn't built with gzip support).
+// Sending the same files uncompressed should work.
+type GzipUnsupportedError struct {
+	Err error
+}
+
+func (e GzipUnsupportedError) Error() string {
+	return fmt.Sprintf("container can't unpack gzipped archive: %v", e.Err)
+}
+
+func (e GzipUnsupportedError) Unwrap() error {
+	return e.Err
+}
+
+func IsGzipUnsupportedError(err error) bool {
+	var gue GzipUnsupportedError
+	return errors.As(err, &gue)
+}
+
+// GNU tar without a gzip binary says "gzip: Cannot exec", and busybox tar
+// built without gzip support rejects the -z flag.
+func isGzipUnsupportedOutput(out *bytes.Buffer) bool {
+	msg := strings.ToLower(out.String())
+	if strings.Contains(msg, "gzip") && (strings.Contains(msg, "cannot exec") || strings.Contains(msg, "not found")) {
+		return true
+	}
+	return strings.Contains(msg, "invalid option") || strings.Contains(msg, "unrecognized option")
+}
+
+// Containers with a read-only root filesystem can only unpack files
+// into writable volumes.
+func isReadOnlyFilesystemError(out *bytes.Buffer, err error) bool {
+	msg := strings.ToLower(fmt.Sprintf("%s\n%s", out.String(), err.Error()))
+	return strings.Contains(msg, "read-only file system")
+}
+
+func readOnlyFilesystemError(err error) error {
+	return fmt.Errorf("%v\n"+
+		"This usually means the container has a read-only root filesystem. Please check:\n"+
+		"  1) That every sync() destination is on a writable volume (e.g., an emptyDir)\n"+
+		"  2) That the Pod spec doesn't set `readOnlyRootFilesystem: true` in its SecurityContext",
+		err)
+}
+
+// Wraps the archive we're copying into the container, and remembers
+// if we failed to write it.
+//
+// Exec clients don't reliably surface stdin errors, so an exec that fails
+// because the archive was truncated looks the same as `tar` failing to unpack
+// it in the container. This lets us tell the two apart.
+//
+// It also checks whether the archive is gzipped, so that we know how to unpack it.
+type archiveReader struct {
+	r       io.Reader
+	gzipped bool
+
+	mu  sync.Mutex
+	err error
+}
+
+func newArchiveReader(r io.Reader) *archiveReader {
+	if r == nil {
+		return &archiveReader{}
+	}
+
+	// If we can't peek, Read will return the same error, so we'll record it there.
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(len(gzipMagic))
+	return &archiveReader{r: br, gzipped: bytes.Equal(magic, gzipMagic)}
+}
+
+func (ar *archiveReader) Read(p []byte) (int, error) {
+	n, err := ar.r.Read(p)
+	if err != nil && err != io.EOF {
+		ar.mu.Lock()
+		if ar.err == nil {
+			ar.err = err
+		}
+		ar.mu.Unlock()
+	}
+	return n, err
+}
+
+// The reader to pass as exec stdin. Preserves nil, so that we don't
+// attach stdin when there's nothing to copy.
+func (ar *archiveReader) stdin() io.Reader {
+	if ar.r == nil {
+		return nil
+	}
+	return ar
+}
+
+// If the copy failed because we couldn't write the archive, return
+// that error instead, so that callers know it wasn't the container's fault.
+// If the container couldn't unpack a gzipped archive, say so, so that
+// callers can retry without compression.
+func (ar *archiveReader) copyErr(out *bytes.Buffer, err error) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+	if build.IsTarWriteError(ar.err) {
+		return ar.err
+	}
+	if ar.gzipped && isGzipUnsupportedOutput(out) {
+		return GzipUnsupportedError{Err: err}
+	}
+	return err
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/ignore"
 	"github.com/tilt-dev/tilt/internal/watch"
 	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
@@ -80,6 +81,16 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	}
 
 	if !hasExisting || !equality.Semantic.DeepEqual(existing.spec, fw.Spec) {
+		if hasExisting {
+			updated, err := c.updateIgnores(existing, &fw)
+			if err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to update filesystem watch ignores: %v", err)
+			}
+			if updated {
+				return ctrl.Result{}, nil
+			}
+		}
+
 		if err := c.addOrReplace(ctx, c.Store, req.NamespacedName, &fw); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to create/update filesystem watch: %v", err)
 		}
@@ -104,6 +115,35 @@ func (c *Controller) removeWatch(tw *watcher) {
 	}
 }
 
+// updateIgnores applies the new ignores to the existing watcher in place, if
+// they're the only thing that changed and the watcher supports it.
+// Restarting the watcher would drop events while it re-adds OS watches.
+//
+// Returns false if the watcher needs to be replaced instead.
+//
+// mu must be held before calling.
+func (c *Controller) updateIgnores(w *watcher, fw *filewatches.FileWatch) (bool, error) {
+	if !equality.Semantic.DeepEqual(w.spec.WatchedPaths, fw.Spec.WatchedPaths) {
+		return false, nil
+	}
+
+	setter, ok := w.notify.(watch.IgnoreSetter)
+	if !ok {
+		return false, nil
+	}
+
+	ignoreMatcher, err := ignore.IgnoresToMatcher(fw.Spec.Ignores)
+	if err != nil {
+		return false, err
+	}
+	setter.SetIgnore(ignoreMatcher)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.spec = *fw.Spec.DeepCopy()
+	return true, nil
+}
+
 func (c *Controller) addOrReplace(ctx context.Context, st store.RStore, name types.NamespacedName, fw *filewatches.FileWatch) error {
 	ignoreMatcher, err := ignore.IgnoresToMatcher(fw.Spec.Ignores)
 	if err != nil {
@@ -112,6 +152,7 @@ func (c *Controller) addOrReplace(ctx context.Context, st store.RStore, name typ
 	notify, err := c.fsWatcherMaker(
 		append([]string{}, fw.Spec.WatchedPaths...),
 		ignoreMatcher,
+		canCoalesceDirs(fw),
 		logger.Get(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to initialize filesystem watch: %v", err)
@@ -151,7 +192,12 @@ func (c *Controller) addOrReplace(ctx context.Context, st store.RStore, name typ
 }
 
 func (c *Controller) dispatchFileChangesLoop(ctx context.Context, st store.RStore, w *watcher) {
-	eventsCh := fsevent.Coalesce(c.timerMaker, w.notify.Events())
+	var eventsCh <-chan []watch.FileEvent
+	if batcher, ok := w.notify.(watch.BatchNotify); ok {
+		eventsCh = fsevent.CoalesceBatches(c.timerMaker, batcher.BatchedEvents())
+	} else {
+		eventsCh = fsevent.Coalesce(c.timerMaker, w.notify.Events())
+	}
 
 	defer func() {
 		c.mu.Lock()
@@ -196,3 +242,14 @@ func (c *Controller) dispatchFileChangesLoop(ctx context.Context, st store.RStor
 		}
 	}
 }
+
+// Local resources and the Tiltfile only care that something changed, so we can
+// report a changed directory instead of each file in it. Image builds and live
+// updates sync the files that changed, so they need every path.
+func canCoalesceDirs(fw *filewatches.FileWatch) bool {
+	id, err := targetID(&fw.ObjectMeta)
+	if err != nil {
+		return false
+	}
+	return id.Type == model.TargetTypeLocal || id.Type == model.TargetTypeConfigs
+}
@@ -266,3 +266,67 @@ func TestController_Reconcile_Watches(t *testing.T) {
 		assert.Equal(t, mostRecentEventTime, updated.Status.LastEventTime.Time)
 	}
 }
+
+func TestController_Reconcile_IgnoresInPlace(t *testing.T) {
+	f := newFixture(t)
+	key, fw := f.CreateSimpleFileWatch()
+
+	f.ChangeAndWaitForSeenFile(key, "a", "1")
+
+	f.MustGet(key, fw)
+	originalStart := fw.Status.MonitorStartTime.Time
+	originalWatcher := f.controller.targetWatches[key]
+
+	fw.Spec.Ignores = []filewatches.IgnoreDef{
+		{
+			BasePath: f.tmpdir.Path(),
+			Patterns: []string{"**/ignore_me"},
+		},
+	}
+	f.Update(fw)
+
+	assert.Same(t, originalWatcher, f.controller.targetWatches[key], "Watcher should be updated in place")
+
+	f.ChangeFile("a", "ignore_me")
+	f.ChangeAndWaitForSeenFile(key, "a", "2")
+
+	var updated filewatches.FileWatch
+	f.MustGet(key, &updated)
+	assert.Equal(t, originalStart, updated.Status.MonitorStartTime.Time, "Filesystem monitor should not restart")
+	if assert.Equal(t, 2, len(updated.Status.FileEvents)) {
+		assert.Equal(t, []string{f.tmpdir.JoinPath("a", "1")}, updated.Status.FileEvents[0].SeenFiles)
+		assert.Equal(t, []string{f.tmpdir.JoinPath("a", "2")}, updated.Status.FileEvents[1].SeenFiles)
+	}
+}
+
+func TestController_CoalesceDirsOnlyForLocalAndConfigs(t *testing.T) {
+	for _, tc := range []struct {
+		targetID string
+		expected bool
+	}{
+		{"local:server", true},
+		{"configs:singleton", true},
+		{"image:frontend", false},
+		{"docker-compose:db", false},
+		{"", false},
+	} {
+		t.Run(tc.targetID, func(t *testing.T) {
+			f := newFixture(t)
+			fw := &filewatches.FileWatch{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   apis.SanitizeName(t.Name()),
+					Name:        "test-file-watch",
+					Annotations: map[string]string{filewatches.AnnotationTargetID: tc.targetID},
+				},
+				Spec: filewatches.FileWatchSpec{
+					WatchedPaths: []string{f.tmpdir.JoinPath("a")},
+				},
+			}
+			f.Create(fw)
+
+			w := f.controller.targetWatches[f.KeyForObject(fw)]
+			require.NotNil(t, w)
+			assert.Equal(t, tc.expected, w.notify.(*fsevent.FakeWatcher).CoalesceDirs)
+		})
+	}
+}
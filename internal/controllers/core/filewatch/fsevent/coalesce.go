@@ -22,16 +22,29 @@ const BufferMaxDuration = 10 * time.Second
 // Coalesce makes an attempt to read some events from `eventChan` so that multiple file changes
 // that happen at the same time from the user's perspective are grouped together.
 func Coalesce(timerMaker TimerMaker, eventChan <-chan watch.FileEvent) <-chan []watch.FileEvent {
+	batchChan := make(chan []watch.FileEvent)
+	go func() {
+		defer close(batchChan)
+		for event := range eventChan {
+			batchChan <- []watch.FileEvent{event}
+		}
+	}()
+	return CoalesceBatches(timerMaker, batchChan)
+}
+
+// CoalesceBatches is like Coalesce, but reads events that the watcher has already
+// batched, so that we only reset the timers once per batch instead of once per file.
+func CoalesceBatches(timerMaker TimerMaker, batchChan <-chan []watch.FileEvent) <-chan []watch.FileEvent {
 	ret := make(chan []watch.FileEvent)
 	go func() {
 		defer close(ret)
 
 		for {
-			event, ok := <-eventChan
+			batch, ok := <-batchChan
 			if !ok {
 				return
 			}
-			events := []watch.FileEvent{event}
+			events := append([]watch.FileEvent{}, batch...)
 
 			// keep grabbing changes until we've gone `BufferMinRestDuration` without seeing a change
 			minRestTimer := timerMaker(BufferMinRestDuration)
@@ -44,12 +57,12 @@ func Coalesce(timerMaker TimerMaker, eventChan <-chan watch.FileEvent) <-chan []
 			channelClosed := false
 			for !done && !channelClosed {
 				select {
-				case event, ok := <-eventChan:
+				case batch, ok := <-batchChan:
 					if !ok {
 						channelClosed = true
 					} else {
 						minRestTimer = timerMaker(BufferMinRestDuration)
-						events = append(events, event)
+						events = append(events, batch...)
 					}
 				case <-minRestTimer:
 					done = true
@@ -7,12 +7,17 @@ import (
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
-type WatcherMaker func(paths []string, ignore watch.PathMatcher, l logger.Logger) (watch.Notify, error)
+// When coalesceDirs is true, the watcher may report a watched directory instead
+// of the files that changed under it.
+type WatcherMaker func(paths []string, ignore watch.PathMatcher, coalesceDirs bool, l logger.Logger) (watch.Notify, error)
 
 type TimerMaker func(d time.Duration) <-chan time.Time
 
 func ProvideWatcherMaker() WatcherMaker {
-	return func(paths []string, ignore watch.PathMatcher, l logger.Logger) (watch.Notify, error) {
+	return func(paths []string, ignore watch.PathMatcher, coalesceDirs bool, l logger.Logger) (watch.Notify, error) {
+		if coalesceDirs {
+			return watch.NewDirWatcher(paths, ignore, l)
+		}
 		return watch.NewWatcher(paths, ignore, l)
 	}
 }
@@ -25,13 +25,14 @@ func NewFakeMultiWatcher() *FakeMultiWatcher {
 	return r
 }
 
-func (w *FakeMultiWatcher) NewSub(paths []string, ignore watch.PathMatcher, _ logger.Logger) (watch.Notify, error) {
+func (w *FakeMultiWatcher) NewSub(paths []string, ignore watch.PathMatcher, coalesceDirs bool, _ logger.Logger) (watch.Notify, error) {
 	subCh := make(chan watch.FileEvent)
 	errorCh := make(chan error)
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
 	watcher := NewFakeWatcher(subCh, errorCh, paths, ignore)
+	watcher.CoalesceDirs = coalesceDirs
 	w.watchers = append(w.watchers, watcher)
 	w.subs = append(w.subs, subCh)
 	w.subsErrors = append(w.subsErrors, errorCh)
@@ -92,7 +93,13 @@ type FakeWatcher struct {
 	outboundCh chan watch.FileEvent
 	errorCh    chan error
 
-	paths  []string
+	paths []string
+
+	// Whether the watcher was asked to coalesce events per directory.
+	// The fake doesn't coalesce; this is just for tests to check.
+	CoalesceDirs bool
+
+	mu     sync.Mutex
 	ignore watch.PathMatcher
 }
 
@@ -111,7 +118,9 @@ func NewFakeWatcher(inboundCh chan watch.FileEvent, errorCh chan error, paths []
 }
 
 func (w *FakeWatcher) matches(path string) bool {
+	w.mu.Lock()
 	ignore, _ := w.ignore.Matches(path)
+	w.mu.Unlock()
 	if ignore {
 		return false
 	}
@@ -141,6 +150,12 @@ func (w *FakeWatcher) Events() chan watch.FileEvent {
 	return w.outboundCh
 }
 
+func (w *FakeWatcher) SetIgnore(ignore watch.PathMatcher) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.ignore = ignore
+}
+
 func (w *FakeWatcher) loop() {
 	var q []watch.FileEvent
 	for {
@@ -160,3 +175,4 @@ func (w *FakeWatcher) loop() {
 }
 
 var _ watch.Notify = &FakeWatcher{}
+var _ watch.IgnoreSetter = &FakeWatcher{}
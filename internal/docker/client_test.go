@@ -8,6 +8,7 @@ import (
 
 	"github.com/docker/docker/api/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/k8s"
@@ -291,3 +292,24 @@ func TestProvideEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestProvideEnvDockerDisabled(t *testing.T) {
+	orig := os.Getenv(DisableDockerEnvVar)
+	os.Setenv(DisableDockerEnvVar, "1")
+	defer os.Setenv(DisableDockerEnvVar, orig)
+
+	ctx := context.Background()
+	mkClient := k8s.FakeMinikube{FakeVersion: "1.8.2"}
+	cEnv := ProvideClusterEnv(ctx, "minikube-me", k8s.EnvMinikube, container.RuntimeDocker, mkClient)
+	assert.EqualError(t, cEnv.Error, "Docker is disabled (TILT_DISABLE_DOCKER is set)")
+	assert.Equal(t, "", cEnv.Host)
+
+	lEnv := ProvideLocalEnv(ctx, "minikube-me", k8s.EnvMinikube, cEnv)
+	assert.EqualError(t, lEnv.Error, "Docker is disabled (TILT_DISABLE_DOCKER is set)")
+
+	lCli := ProvideLocalCli(ctx, lEnv)
+	cCli, err := ProvideClusterCli(ctx, lEnv, cEnv, lCli)
+	require.NoError(t, err)
+	assert.EqualError(t, lCli.CheckConnected(), "Docker is disabled (TILT_DISABLE_DOCKER is set)")
+	assert.EqualError(t, cCli.CheckConnected(), "Docker is disabled (TILT_DISABLE_DOCKER is set)")
+}
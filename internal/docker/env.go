@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/blang/semver"
 	"github.com/docker/cli/opts"
@@ -82,7 +83,24 @@ func (e Env) AsEnviron() []string {
 type ClusterEnv Env
 type LocalEnv Env
 
+// Set TILT_DISABLE_DOCKER=1 to never connect to a Docker daemon (e.g., on a
+// machine without Docker that only deploys to a remote cluster and live
+// updates with kubectl exec). Anything that needs Docker fails with an
+// error that says so.
+const DisableDockerEnvVar = "TILT_DISABLE_DOCKER"
+
+var errDockerDisabled = fmt.Errorf("Docker is disabled (%s is set)", DisableDockerEnvVar)
+
+func IsDockerDisabled() bool {
+	disabled, err := strconv.ParseBool(os.Getenv(DisableDockerEnvVar))
+	return err == nil && disabled
+}
+
 func ProvideLocalEnv(ctx context.Context, kubeContext k8s.KubeContext, env k8s.Env, cEnv ClusterEnv) LocalEnv {
+	if IsDockerDisabled() {
+		return LocalEnv{Error: errDockerDisabled}
+	}
+
 	result := overlayOSEnvVars(Env{})
 
 	// The user may have already configured their local docker client
@@ -101,6 +119,10 @@ func ProvideLocalEnv(ctx context.Context, kubeContext k8s.KubeContext, env k8s.E
 }
 
 func ProvideClusterEnv(ctx context.Context, kubeContext k8s.KubeContext, env k8s.Env, runtime container.Runtime, minikubeClient k8s.MinikubeClient) ClusterEnv {
+	if IsDockerDisabled() {
+		return ClusterEnv{Error: errDockerDisabled}
+	}
+
 	result := Env{}
 
 	if runtime == container.RuntimeDocker {
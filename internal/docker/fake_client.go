@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/docker/go-units"
@@ -82,6 +83,9 @@ type ExecCall struct {
 }
 
 type FakeClient struct {
+	// Live Update copies to and execs in several containers at once.
+	mu sync.Mutex
+
 	FakeEnv Env
 
 	PushCount   int
@@ -199,11 +203,16 @@ func (c *FakeClient) ContainerList(ctx context.Context, options types.ContainerL
 }
 
 func (c *FakeClient) ContainerRestartNoWait(ctx context.Context, containerID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.RestartsByContainer[containerID]++
 	return nil
 }
 
 func (c *FakeClient) ExecInContainer(ctx context.Context, cID container.ID, cmd model.Cmd, in io.Reader, out io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if cmd.Argv[0] == "tar" {
 		c.CopyCount++
 		c.CopyContainer = string(cID)
@@ -8,6 +8,7 @@ import (
 	"go.opentelemetry.io/otel/api/core"
 	"go.opentelemetry.io/otel/api/trace"
 
+	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
 	"github.com/tilt-dev/tilt/internal/k8s"
@@ -48,10 +49,23 @@ func NewCompositeBuildAndDeployer(builders BuildOrder, tracer trace.Tracer) *Com
 	return &CompositeBuildAndDeployer{builders: builders, tracer: tracer}
 }
 
+// A BuildAndDeployer that can tell us why a Live Update fell back to a full build.
+type fallBackReporter interface {
+	buildAndDeployWithFallBackReason(ctx context.Context, st store.RStore, specs []model.TargetSpec, currentState store.BuildStateSet) (store.BuildResultSet, model.FallBackReason, error)
+}
+
+var _ fallBackReporter = &CompositeBuildAndDeployer{}
+
 func (composite *CompositeBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.RStore, specs []model.TargetSpec, currentState store.BuildStateSet) (store.BuildResultSet, error) {
+	br, _, err := composite.buildAndDeployWithFallBackReason(ctx, st, specs, currentState)
+	return br, err
+}
+
+func (composite *CompositeBuildAndDeployer) buildAndDeployWithFallBackReason(ctx context.Context, st store.RStore, specs []model.TargetSpec, currentState store.BuildStateSet) (store.BuildResultSet, model.FallBackReason, error) {
 	ctx, span := composite.tracer.Start(ctx, "update")
 	defer span.End()
 	var lastErr, lastUnexpectedErr error
+	fallBackReason := model.FallBackReasonNone
 
 	specNames := []string{}
 
@@ -71,11 +85,11 @@ func (composite *CompositeBuildAndDeployer) BuildAndDeploy(ctx context.Context,
 			for _, bt := range buildTypes {
 				span.SetAttributes(core.KeyValue{Key: core.Key(fmt.Sprintf("buildType.%s", bt)), Value: core.Bool(true)})
 			}
-			return br, nil
+			return br, fallBackReason, nil
 		}
 
 		if !buildcontrol.ShouldFallBackForErr(err) {
-			return br, err
+			return br, fallBackReason, err
 		}
 
 		_, isLiveUpdate := builder.(*buildcontrol.LiveUpdateBuildAndDeployer)
@@ -88,9 +102,13 @@ func (composite *CompositeBuildAndDeployer) BuildAndDeploy(ctx context.Context,
 					"Falling back to a full image build + deploy\n", err)
 			}
 			l.Write(redirectErr.Level, []byte(s))
+			if isLiveUpdate {
+				fallBackReason = redirectErr.FallBackReason
+			}
 		} else {
 			lastUnexpectedErr = err
 			if isLiveUpdate {
+				fallBackReason = model.FallBackReasonExecError
 				// Indent the error message.
 				errMsg := strings.Replace(strings.TrimSpace(fmt.Sprintf("%v", err)), "\n", "\n\t", -1)
 				l.Warnf("Live Update failed with unexpected error:\n\t%s\n"+
@@ -99,14 +117,19 @@ func (composite *CompositeBuildAndDeployer) BuildAndDeploy(ctx context.Context,
 				logger.Get(ctx).Infof("got unexpected error during build/deploy: %v", err)
 			}
 		}
+		if isLiveUpdate && fallBackReason != model.FallBackReasonNone {
+			analytics.Get(ctx).Incr("build.fallback", map[string]string{
+				"reason": string(fallBackReason),
+			})
+		}
 		lastErr = err
 	}
 
 	if lastUnexpectedErr != nil {
 		// The most interesting error is the last UNEXPECTED error we got
-		return store.BuildResultSet{}, lastUnexpectedErr
+		return store.BuildResultSet{}, fallBackReason, lastUnexpectedErr
 	}
-	return store.BuildResultSet{}, lastErr
+	return store.BuildResultSet{}, fallBackReason, lastErr
 }
 
 func DefaultBuildOrder(lubad *buildcontrol.LiveUpdateBuildAndDeployer, ibad *buildcontrol.ImageBuildAndDeployer, dcbad *buildcontrol.DockerComposeBuildAndDeployer,
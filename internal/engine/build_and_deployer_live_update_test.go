@@ -486,9 +486,11 @@ func TestLiveUpdateMultipleContainersFallsBackForFailure(t *testing.T) {
 		runningContainersByTarget: map[model.TargetID][]container.ID{m.ImageTargetAt(0).ID(): cIDs},
 		changedFiles:              []string{"a.txt"},
 
-		// attempted container update; called copy and exec before hitting error
-		expectDockerCopyCount: 1,
-		expectDockerExecCount: 1,
+		// all containers are updated at once; one hit the error,
+		// the other two succeeded (copy, exec, restart)
+		expectDockerCopyCount:    3,
+		expectDockerExecCount:    3,
+		expectDockerRestartCount: 2,
 
 		// fell back to image build
 		expectDockerBuildCount: 1,
@@ -513,11 +515,12 @@ func TestLiveUpdateMultipleContainersFallsBackForFailureAfterSuccess(t *testing.
 		runningContainersByTarget: map[model.TargetID][]container.ID{m.ImageTargetAt(0).ID(): cIDs},
 		changedFiles:              []string{"a.txt"},
 
-		// one successful update (copy, exec, restart);
+		// all containers are updated at once;
+		// two successful updates (copy, exec, restart),
 		// one truncated update (copy, exec) before hitting error
-		expectDockerCopyCount:    2,
-		expectDockerExecCount:    2,
-		expectDockerRestartCount: 1,
+		expectDockerCopyCount:    3,
+		expectDockerExecCount:    3,
+		expectDockerRestartCount: 2,
 
 		// fell back to image build
 		expectDockerBuildCount: 1,
@@ -575,11 +578,11 @@ func TestLiveUpdateMultipleContainersFallsBackForSomeUserRunFailuresSomeSuccess(
 		runningContainersByTarget: map[model.TargetID][]container.ID{m.ImageTargetAt(0).ID(): cIDs},
 		changedFiles:              []string{"a.txt"},
 
-		// one truncated update (copy and exec before hitting error)
+		// all containers are updated at once;
+		// two truncated updates (copy and exec before hitting error)
 		// one successful update (copy, exec, restart)
-		// fall back before attempting third update
-		expectDockerCopyCount:    2,
-		expectDockerExecCount:    2,
+		expectDockerCopyCount:    3,
+		expectDockerExecCount:    3,
 		expectDockerRestartCount: 1,
 
 		// fell back to image build
@@ -606,10 +609,10 @@ func TestLiveUpdateMultipleContainersFallsBackForSomeUserRunFailuresSomeNonUserF
 		runningContainersByTarget: map[model.TargetID][]container.ID{m.ImageTargetAt(0).ID(): cIDs},
 		changedFiles:              []string{"a.txt"},
 
-		// two truncated updates (copy and exec before hitting error)
-		// fall back before attempting third update
-		expectDockerCopyCount:    2,
-		expectDockerExecCount:    2,
+		// all containers are updated at once;
+		// three truncated updates (copy and exec before hitting error)
+		expectDockerCopyCount:    3,
+		expectDockerExecCount:    3,
 		expectDockerRestartCount: 0,
 
 		// fell back to image build
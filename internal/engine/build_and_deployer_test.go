@@ -26,6 +26,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
 
+	"github.com/tilt-dev/wmclient/pkg/analytics"
 	"github.com/tilt-dev/wmclient/pkg/dirs"
 
 	"github.com/tilt-dev/tilt/internal/container"
@@ -214,7 +215,7 @@ func TestLiveUpdateFallbackMessagingRedirect(t *testing.T) {
 	bs := resultToStateSet(alreadyBuiltSet, []string{changed}, testContainerInfo)
 
 	targets := buildcontrol.BuildTargets(manifest)
-	_, err := f.BuildAndDeploy(targets, bs)
+	_, reason, err := f.BuildAndDeployWithFallBackReason(targets, bs)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -226,6 +227,12 @@ func TestLiveUpdateFallbackMessagingRedirect(t *testing.T) {
 
 	assert.Contains(t, f.logs.String(), "Will not perform Live Update because",
 		"expect logs to contain Live Update-specific fallback message")
+	assert.Equal(t, model.FallBackReasonStopPath, reason)
+	assert.Contains(t, f.ma.Counts, analytics.CountEvent{
+		Name: "build.fallback",
+		Tags: map[string]string{"reason": "stop-path"},
+		N:    1,
+	})
 }
 
 func TestLiveUpdateFallbackMessagingUnexpectedError(t *testing.T) {
@@ -239,7 +246,7 @@ func TestLiveUpdateFallbackMessagingUnexpectedError(t *testing.T) {
 
 	manifest := NewSanchoLiveUpdateManifest(f)
 	targets := buildcontrol.BuildTargets(manifest)
-	_, err := f.BuildAndDeploy(targets, bs)
+	_, reason, err := f.BuildAndDeployWithFallBackReason(targets, bs)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -251,6 +258,7 @@ func TestLiveUpdateFallbackMessagingUnexpectedError(t *testing.T) {
 
 	assert.Contains(t, f.logs.String(), "Live Update failed with unexpected error",
 		"expect logs to contain Live Update-specific fallback message")
+	assert.Equal(t, model.FallBackReasonExecError, reason)
 }
 
 func TestLiveUpdateTwice(t *testing.T) {
@@ -682,10 +690,11 @@ func TestLiveUpdateMultipleImagesOneWithUnsyncedChangeFileFallsBackToImageBuild(
 
 	manifest, bs := multiImageLiveUpdateManifestAndBuildState(f)
 	bs[manifest.ImageTargetAt(1).ID()].FilesChangedSet["/not/synced"] = true // changed file not in a sync --> fall back to image build
-	_, err := f.BuildAndDeploy(buildcontrol.BuildTargets(manifest), bs)
+	_, reason, err := f.BuildAndDeployWithFallBackReason(buildcontrol.BuildTargets(manifest), bs)
 	if err != nil {
 		t.Fatal(err)
 	}
+	assert.Equal(t, model.FallBackReasonNoMatch, reason)
 
 	// expect image build (2x images) when we fall back from failed LiveUpdate
 	assert.Equal(t, 2, f.docker.BuildCount)
@@ -791,6 +800,7 @@ type bdFixture struct {
 	st         *testStore
 	dcCli      *dockercompose.FakeDCClient
 	logs       *bytes.Buffer
+	ma         *analytics.MemoryAnalytics
 	ctrlClient ctrlclient.Client
 }
 
@@ -800,7 +810,7 @@ func newBDFixture(t *testing.T, env k8s.Env, runtime container.Runtime) *bdFixtu
 
 func newBDFixtureWithUpdateMode(t *testing.T, env k8s.Env, runtime container.Runtime, um buildcontrol.UpdateMode) *bdFixture {
 	logs := new(bytes.Buffer)
-	ctx, _, ta := testutils.ForkedCtxAndAnalyticsForTest(logs)
+	ctx, ma, ta := testutils.ForkedCtxAndAnalyticsForTest(logs)
 	ctx, cancel := context.WithCancel(ctx)
 	f := tempdir.NewTempDirFixture(t)
 	dir := dirs.NewTiltDevDirAt(f.Path())
@@ -833,6 +843,7 @@ func newBDFixtureWithUpdateMode(t *testing.T, env k8s.Env, runtime container.Run
 		st:             st,
 		dcCli:          dcc,
 		logs:           logs,
+		ma:             ma,
 		ctrlClient:     ctrlClient,
 	}
 }
@@ -886,6 +897,16 @@ func (f *bdFixture) upsert(obj ctrlclient.Object) {
 }
 
 func (f *bdFixture) BuildAndDeploy(specs []model.TargetSpec, stateSet store.BuildStateSet) (store.BuildResultSet, error) {
+	f.upsertSpecs(specs, stateSet)
+	return f.bd.BuildAndDeploy(f.ctx, f.st, specs, stateSet)
+}
+
+func (f *bdFixture) BuildAndDeployWithFallBackReason(specs []model.TargetSpec, stateSet store.BuildStateSet) (store.BuildResultSet, model.FallBackReason, error) {
+	f.upsertSpecs(specs, stateSet)
+	return f.bd.(fallBackReporter).buildAndDeployWithFallBackReason(f.ctx, f.st, specs, stateSet)
+}
+
+func (f *bdFixture) upsertSpecs(specs []model.TargetSpec, stateSet store.BuildStateSet) {
 	for _, spec := range specs {
 		localTarget, ok := spec.(model.LocalTarget)
 		if ok && localTarget.UpdateCmdSpec != nil {
@@ -919,7 +940,6 @@ func (f *bdFixture) BuildAndDeploy(specs []model.TargetSpec, stateSet store.Buil
 			f.upsert(&ka)
 		}
 	}
-	return f.bd.BuildAndDeploy(f.ctx, f.st, specs, stateSet)
 }
 
 func (f *bdFixture) createBuildStateSet(manifest model.Manifest, changedFiles []string) store.BuildStateSet {
@@ -25,6 +25,9 @@ type BuildCompleteAction struct {
 	Result       store.BuildResultSet
 	FinishTime   time.Time
 	Error        error
+
+	// If a Live Update fell back to a full build, why.
+	FallBackReason model.FallBackReason
 }
 
 func (BuildCompleteAction) Action() {}
@@ -390,7 +390,7 @@ func IsLiveUpdateTargetWaitingOnDeploy(state store.EngineState, mt *store.Manife
 		}
 
 		// If any changed files match a FallBackOn file, fall back to next BuildAndDeployer
-		anyMatch, _, err := luInfo.FallBackOnFiles().AnyMatch(files)
+		anyMatch, _, err := luInfo.FallBackOnFiles().AnyGlobMatch(files)
 		if err != nil || anyMatch {
 			return false
 		}
@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 // Nothing is on fire, this is an expected case like a container builder being
@@ -15,6 +16,9 @@ import (
 type RedirectToNextBuilder struct {
 	error
 	Level logger.Level
+
+	// If this redirect makes a Live Update fall back to a full build, why.
+	FallBackReason model.FallBackReason
 }
 
 // UserFacing indicates whether this error should be messaged to the user by default.
@@ -24,17 +28,22 @@ func (redir RedirectToNextBuilder) UserFacing() bool {
 	return redir.Level.AsSevereAs(logger.InfoLvl)
 }
 
+func (redir RedirectToNextBuilder) WithFallBackReason(reason model.FallBackReason) RedirectToNextBuilder {
+	redir.FallBackReason = reason
+	return redir
+}
+
 func WrapRedirectToNextBuilder(err error, level logger.Level) RedirectToNextBuilder {
-	return RedirectToNextBuilder{err, level}
+	return RedirectToNextBuilder{error: err, Level: level}
 }
 
 func SilentRedirectToNextBuilderf(msg string, a ...interface{}) RedirectToNextBuilder {
 	// Only show to user in Debug mode
-	return RedirectToNextBuilder{fmt.Errorf(msg, a...), logger.DebugLvl}
+	return RedirectToNextBuilder{error: fmt.Errorf(msg, a...), Level: logger.DebugLvl}
 }
 
 func RedirectToNextBuilderInfof(msg string, a ...interface{}) RedirectToNextBuilder {
-	return RedirectToNextBuilder{fmt.Errorf(msg, a...), logger.InfoLvl}
+	return RedirectToNextBuilder{error: fmt.Errorf(msg, a...), Level: logger.InfoLvl}
 }
 
 var _ error = RedirectToNextBuilder{}
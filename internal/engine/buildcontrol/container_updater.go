@@ -0,0 +1,116 @@
+package buildcontrol
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tilt-dev/tilt/internal/containerupdate"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Picks the ContainerUpdater that copies files into running containers.
+//
+// Bound in wire, so that clusters where neither the Docker socket nor
+// kubectl exec is a good fit (e.g., containerd-only clusters) can
+// bind their own updater without touching the live update code.
+type ContainerUpdaterSelector interface {
+	ContainerUpdaterForSpecs(ctx context.Context, specs []model.TargetSpec) containerupdate.ContainerUpdater
+}
+
+// Uses the Docker updater when we can talk to the container runtime directly,
+// and kubectl exec otherwise.
+type DefaultContainerUpdaterSelector struct {
+	dcu         *containerupdate.DockerUpdater
+	ecu         *containerupdate.ExecUpdater
+	updMode     UpdateMode
+	kubeContext k8s.KubeContext
+
+	// The images we've already warned can't use the updater they asked for,
+	// so that we don't warn on every live update.
+	mu     sync.Mutex
+	warned map[model.TargetID]bool
+}
+
+func NewDefaultContainerUpdaterSelector(dcu *containerupdate.DockerUpdater,
+	ecu *containerupdate.ExecUpdater,
+	updMode UpdateMode,
+	kubeContext k8s.KubeContext) *DefaultContainerUpdaterSelector {
+	return &DefaultContainerUpdaterSelector{
+		dcu:         dcu,
+		ecu:         ecu,
+		updMode:     updMode,
+		kubeContext: kubeContext,
+		warned:      make(map[model.TargetID]bool),
+	}
+}
+
+func (s *DefaultContainerUpdaterSelector) ContainerUpdaterForSpecs(ctx context.Context, specs []model.TargetSpec) containerupdate.ContainerUpdater {
+	isDC := len(model.ExtractDockerComposeTargets(specs)) > 0
+	if isDC {
+		return s.dcu
+	}
+
+	id, updater := liveUpdateUpdater(specs)
+	switch updater {
+	case model.LiveUpdateUpdaterDocker:
+		if s.dcu.WillBuildToKubeContext(s.kubeContext) {
+			return s.dcu
+		}
+
+		// The Docker daemon we talk to doesn't run this cluster's containers
+		// (or Docker is disabled), so copying files with it can't work.
+		reason := "the local Docker daemon doesn't run the containers of cluster " + string(s.kubeContext)
+		if docker.IsDockerDisabled() {
+			reason = docker.DisableDockerEnvVar + " is set"
+		}
+		if s.shouldWarn(id) {
+			logger.Get(ctx).Warnf("Ignoring live_update_updater=%q (%s). Using kubectl exec instead.",
+				model.LiveUpdateUpdaterDocker, reason)
+		}
+		return s.ecu
+	case model.LiveUpdateUpdaterExec:
+		return s.ecu
+	}
+
+	if s.updMode == UpdateModeContainer {
+		return s.dcu
+	}
+
+	if s.updMode == UpdateModeKubectlExec {
+		return s.ecu
+	}
+
+	if s.dcu.WillBuildToKubeContext(s.kubeContext) {
+		return s.dcu
+	}
+
+	return s.ecu
+}
+
+// Returns true the first time it's called for an image.
+func (s *DefaultContainerUpdaterSelector) shouldWarn(id model.TargetID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.warned[id] {
+		return false
+	}
+	s.warned[id] = true
+	return true
+}
+
+// The updater that the manifest's live_update asked for, if any,
+// and the image that asked for it.
+func liveUpdateUpdater(specs []model.TargetSpec) (model.TargetID, model.LiveUpdateUpdater) {
+	for _, iTarget := range model.ExtractImageTargets(specs) {
+		u := iTarget.LiveUpdateInfo().Updater
+		if u != model.LiveUpdateUpdaterAuto {
+			return iTarget.ID(), u
+		}
+	}
+	return model.TargetID{}, model.LiveUpdateUpdaterAuto
+}
+
+var _ ContainerUpdaterSelector = &DefaultContainerUpdaterSelector{}
@@ -0,0 +1,78 @@
+package buildcontrol
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/containerupdate"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestContainerUpdaterForUpdateMode(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	dcu := &containerupdate.DockerUpdater{}
+	ecu := &containerupdate.ExecUpdater{}
+	k8sSpecs := []model.TargetSpec{model.K8sTarget{}}
+
+	s := NewDefaultContainerUpdaterSelector(dcu, ecu, UpdateModeContainer, k8s.KubeContext("fake-context"))
+	assert.Same(t, dcu, s.ContainerUpdaterForSpecs(ctx, k8sSpecs))
+
+	s = NewDefaultContainerUpdaterSelector(dcu, ecu, UpdateModeKubectlExec, k8s.KubeContext("fake-context"))
+	assert.Same(t, ecu, s.ContainerUpdaterForSpecs(ctx, k8sSpecs))
+}
+
+func TestContainerUpdaterForDockerCompose(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	dcu := &containerupdate.DockerUpdater{}
+	ecu := &containerupdate.ExecUpdater{}
+	dcSpecs := []model.TargetSpec{model.DockerComposeTarget{}}
+
+	s := NewDefaultContainerUpdaterSelector(dcu, ecu, UpdateModeKubectlExec, k8s.KubeContext("fake-context"))
+	assert.Same(t, dcu, s.ContainerUpdaterForSpecs(ctx, dcSpecs))
+}
+
+func TestContainerUpdaterForLiveUpdateUpdater(t *testing.T) {
+	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	dCli := docker.NewFakeClient()
+	dCli.FakeEnv = docker.Env{BuildToKubeContexts: []string{"fake-context"}}
+	dcu := containerupdate.NewDockerUpdater(dCli)
+	ecu := &containerupdate.ExecUpdater{}
+
+	s := NewDefaultContainerUpdaterSelector(dcu, ecu, UpdateModeKubectlExec, k8s.KubeContext("fake-context"))
+	assert.Same(t, dcu, s.ContainerUpdaterForSpecs(ctx, specsWithUpdater(model.LiveUpdateUpdaterDocker)))
+	assert.Same(t, ecu, s.ContainerUpdaterForSpecs(ctx, specsWithUpdater(model.LiveUpdateUpdaterAuto)))
+
+	s = NewDefaultContainerUpdaterSelector(dcu, ecu, UpdateModeContainer, k8s.KubeContext("fake-context"))
+	assert.Same(t, ecu, s.ContainerUpdaterForSpecs(ctx, specsWithUpdater(model.LiveUpdateUpdaterExec)))
+	assert.Same(t, dcu, s.ContainerUpdaterForSpecs(ctx, specsWithUpdater(model.LiveUpdateUpdaterAuto)))
+}
+
+func TestContainerUpdaterDockerUpdaterCantReachCluster(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	ctx := logger.WithLogger(context.Background(), logger.NewTestLogger(out))
+	dcu := containerupdate.NewDockerUpdater(docker.NewFakeClient())
+	ecu := &containerupdate.ExecUpdater{}
+
+	s := NewDefaultContainerUpdaterSelector(dcu, ecu, UpdateModeAuto, k8s.KubeContext("remote-context"))
+	assert.Same(t, ecu, s.ContainerUpdaterForSpecs(ctx, specsWithUpdater(model.LiveUpdateUpdaterDocker)))
+	assert.Contains(t, out.String(), `Ignoring live_update_updater="docker"`)
+	assert.Contains(t, out.String(), "remote-context")
+
+	// We only warn once per image.
+	assert.Same(t, ecu, s.ContainerUpdaterForSpecs(ctx, specsWithUpdater(model.LiveUpdateUpdaterDocker)))
+	assert.Equal(t, 1, strings.Count(out.String(), "Ignoring live_update_updater"))
+}
+
+func specsWithUpdater(u model.LiveUpdateUpdater) []model.TargetSpec {
+	lu := model.LiveUpdate{Updater: u}
+	iTarget := model.ImageTarget{}.WithBuildDetails(model.DockerBuild{LiveUpdate: lu})
+	return []model.TargetSpec{iTarget, model.K8sTarget{}}
+}
@@ -1,8 +1,16 @@
 package buildcontrol
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/distribution/reference"
@@ -17,7 +25,6 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/ignore"
-	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -26,24 +33,21 @@ import (
 var _ BuildAndDeployer = &LiveUpdateBuildAndDeployer{}
 
 type LiveUpdateBuildAndDeployer struct {
-	dcu         *containerupdate.DockerUpdater
-	ecu         *containerupdate.ExecUpdater
-	updMode     UpdateMode
-	kubeContext k8s.KubeContext
-	clock       build.Clock
+	cus   ContainerUpdaterSelector
+	clock build.Clock
+
+	// The most containers we'll update at the same time.
+	maxParallelUpdates int
 }
 
-func NewLiveUpdateBuildAndDeployer(dcu *containerupdate.DockerUpdater,
-	ecu *containerupdate.ExecUpdater,
-	updMode UpdateMode,
-	kubeContext k8s.KubeContext,
-	c build.Clock) *LiveUpdateBuildAndDeployer {
+const defaultMaxParallelUpdates = 8
+
+func NewLiveUpdateBuildAndDeployer(cus ContainerUpdaterSelector, c build.Clock) *LiveUpdateBuildAndDeployer {
 	return &LiveUpdateBuildAndDeployer{
-		dcu:         dcu,
-		ecu:         ecu,
-		updMode:     updMode,
-		kubeContext: kubeContext,
-		clock:       c,
+		cus:   cus,
+		clock: c,
+
+		maxParallelUpdates: defaultMaxParallelUpdates,
 	}
 }
 
@@ -54,6 +58,19 @@ type liveUpdInfo struct {
 	changedFiles []build.PathMapping
 	runs         []model.Run
 	hotReload    bool
+	gzip         bool
+
+	// If non-empty, the signal to send to the main process after updating.
+	signal string
+
+	// If true, the user paused live updates for this image, so we skip the update.
+	paused bool
+
+	// The changed files we skipped because live updates were paused.
+	pausedFiles []string
+
+	// Files that the local_pre_sync cmds wrote under a sync source.
+	localPreSyncFiles []string
 }
 
 func (lui liveUpdInfo) Empty() bool { return lui.iTarget.ID() == model.ImageTarget{}.ID() }
@@ -64,7 +81,7 @@ func (lubad *LiveUpdateBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 		return store.BuildResultSet{}, err
 	}
 
-	containerUpdater := lubad.containerUpdaterForSpecs(specs)
+	containerUpdater := lubad.cus.ContainerUpdaterForSpecs(ctx, specs)
 	liveUpdInfos := make([]liveUpdInfo, 0, len(liveUpdateStateSet))
 
 	if len(liveUpdateStateSet) == 0 {
@@ -82,6 +99,44 @@ func (lubad *LiveUpdateBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 		}
 	}
 
+	state := st.RLockState()
+	updateSettings := state.UpdateSettings
+	for i, info := range liveUpdInfos {
+		liveUpdInfos[i].paused = isLiveUpdatePaused(state, info.iTarget.ID())
+	}
+	st.RUnlockState()
+
+	for i, info := range liveUpdInfos {
+		if info.paused {
+			continue
+		}
+
+		err := checkLiveUpdateLimits(info, updateSettings)
+		if err != nil {
+			return store.BuildResultSet{}, err
+		}
+
+		err = checkSyncCollisions(ctx, info, updateSettings)
+		if err != nil {
+			return store.BuildResultSet{}, err
+		}
+
+		liveUpdInfos[i].gzip, err = shouldGzip(info, updateSettings)
+		if err != nil {
+			return store.BuildResultSet{}, err
+		}
+	}
+
+	if updateSettings.LiveUpdateSkipRuns() {
+		for i, info := range liveUpdInfos {
+			if len(info.runs) > 0 {
+				logger.Get(ctx).Infof("Skipping %d run step(s) for %s (live_update_skip_runs is set)",
+					len(info.runs), info.iTarget.ID())
+				liveUpdInfos[i].runs = nil
+			}
+		}
+	}
+
 	ps := build.NewPipelineState(ctx, len(liveUpdInfos), lubad.clock)
 	err = nil
 	defer func() {
@@ -89,9 +144,28 @@ func (lubad *LiveUpdateBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 	}()
 
 	var dontFallBackErr error
-	for _, info := range liveUpdInfos {
+	for i := range liveUpdInfos {
+		info := &liveUpdInfos[i]
+		if info.paused {
+			// Changes that need a full rebuild still get one, because we only
+			// get here once we've decided that the files can be live-updated.
+			logger.Get(ctx).Infof("Live updates paused for %s. Skipping sync of %d changed file(s) until they resume",
+				reference.FamiliarName(info.iTarget.Refs.ClusterRef()), len(info.changedFiles))
+			info.pausedFiles = build.PathMappingsToLocalPaths(info.changedFiles)
+			continue
+		}
+
 		ps.StartPipelineStep(ctx, "updating image %s", reference.FamiliarName(info.iTarget.Refs.ClusterRef()))
-		err = lubad.buildAndDeploy(ctx, ps, containerUpdater, info.iTarget, info.state, info.changedFiles, info.runs, info.hotReload)
+		err = lubad.runLocalPreSync(ctx, info)
+		if err == nil {
+			err = lubad.buildAndDeploy(ctx, ps, containerUpdater, *info)
+		}
+		if isInconsistentUpdateError(err) && updateSettings.LiveUpdateToleratePartialFailure() {
+			// The user told us their run steps are safe to re-run, so report the
+			// failure and let the next change retry, instead of rebuilding.
+			logger.Get(ctx).Infof("  → Not rebuilding (live_update_tolerate_partial_failure is set)")
+			err = WrapDontFallBackError(err)
+		}
 		if err != nil {
 			if !IsDontFallBackError(err) {
 				// something went wrong, we want to fall back -- bail and
@@ -111,56 +185,113 @@ func (lubad *LiveUpdateBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 	return createResultSet(liveUpdateStateSet, liveUpdInfos), err
 }
 
-func (lubad *LiveUpdateBuildAndDeployer) buildAndDeploy(ctx context.Context, ps *build.PipelineState, cu containerupdate.ContainerUpdater, iTarget model.ImageTarget, state store.BuildState, changedFiles []build.PathMapping, runs []model.Run, hotReload bool) (err error) {
-	startTime := time.Now()
+func (lubad *LiveUpdateBuildAndDeployer) buildAndDeploy(ctx context.Context, ps *build.PipelineState, cu containerupdate.ContainerUpdater, info liveUpdInfo) (err error) {
+	startTime := lubad.clock.Now()
 	defer func() {
-		analytics.Get(ctx).Timer("build.container", time.Since(startTime), map[string]string{
+		analytics.Get(ctx).Timer("build.container", lubad.clock.Now().Sub(startTime), map[string]string{
 			"hasError": fmt.Sprintf("%t", err != nil),
 		})
 	}()
 
 	l := logger.Get(ctx)
-	cIDStr := container.ShortStrs(store.IDsForInfos(state.RunningContainers))
+	cIDStr := container.ShortStrs(store.IDsForInfos(info.state.RunningContainers))
 	suffix := ""
-	if len(state.RunningContainers) != 1 {
+	if len(info.state.RunningContainers) != 1 {
 		suffix = "(s)"
 	}
 	ps.StartBuildStep(ctx, "Updating container%s: %s", suffix, cIDStr)
 
-	filter := ignore.CreateBuildContextFilter(iTarget)
-	boiledSteps, err := build.BoilRuns(runs, changedFiles)
+	filter := ignore.CreateBuildContextFilter(info.iTarget)
+	boiledSteps, err := build.BoilRuns(info.runs, info.changedFiles)
 	if err != nil {
 		return err
 	}
 
 	// rm files from container
-	toRemove, toArchive, err := build.MissingLocalPaths(ctx, changedFiles)
+	toRemove, toArchive, err := build.MissingLocalPaths(ctx, info.changedFiles)
 	if err != nil {
 		return errors.Wrap(err, "MissingLocalPaths")
 	}
 
+	syncs := info.iTarget.LiveUpdateInfo().SyncSteps()
+	toRemove, shared, err := build.SplitSharedRemovals(toRemove, syncs)
+	if err != nil {
+		return err
+	}
+
+	// With a single sync, there's no question which one claimed the file.
+	var syncIndexes map[string]int
+	if len(syncs) > 1 {
+		syncIndexes = build.SyncIndexesForPathMappings(info.changedFiles, syncs)
+	}
+	if len(shared) > 0 {
+		l.Infof("Won't delete %d path(s) from container%s that other syncs still have files in: %s", len(shared), suffix, cIDStr)
+		for _, pm := range shared {
+			l.Infof("- '%s' (matched local path: '%s')%s", pm.ContainerPath, pm.LocalPath, syncRuleSuffix(syncIndexes, pm))
+		}
+	}
+
 	if len(toRemove) > 0 {
 		l.Infof("Will delete %d file(s) from container%s: %s", len(toRemove), suffix, cIDStr)
 		for _, pm := range toRemove {
-			l.Infof("- '%s' (matched local path: '%s')", pm.ContainerPath, pm.LocalPath)
+			l.Infof("- '%s' (matched local path: '%s')%s", pm.ContainerPath, pm.LocalPath, syncRuleSuffix(syncIndexes, pm))
 		}
 	}
 
 	if len(toArchive) > 0 {
 		l.Infof("Will copy %d file(s) to container%s: %s", len(toArchive), suffix, cIDStr)
 		for _, pm := range toArchive {
-			l.Infof("- %s", pm.PrettyStr())
+			l.Infof("- %s%s", pm.PrettyStr(), syncRuleSuffix(syncIndexes, pm))
 		}
 	}
 
+	toRemovePaths := build.PathMappingsToContainerPaths(toRemove)
+	newArchive := func(gzip bool) io.Reader {
+		if gzip {
+			return build.GzipTarArchiveForPaths(ctx, toArchive, filter)
+		}
+		return build.TarArchiveForPaths(ctx, toArchive, filter)
+	}
+	if len(info.state.RunningContainers) > 1 {
+		// Read each file once, not once per container.
+		newArchive = newArchiveCache(newArchive).get
+	}
+	results := lubad.updateContainers(ctx, info.state.RunningContainers, func(cInfo store.ContainerInfo) error {
+		archive := newArchive(info.gzip)
+		err := cu.UpdateContainer(ctx, cInfo, archive, toRemovePaths, boiledSteps, info.hotReload)
+		if build.IsTarWriteError(err) {
+			// We couldn't write the files locally, but nothing went wrong in the
+			// container, so it's worth retrying before falling back to a full build.
+			l.Infof("  → Failed to copy files to container %s, retrying: %v", cInfo.ContainerID.ShortStr(), err)
+			archive = newArchive(info.gzip)
+			err = cu.UpdateContainer(ctx, cInfo, archive, toRemovePaths, boiledSteps, info.hotReload)
+		}
+		if containerupdate.IsGzipUnsupportedError(err) {
+			l.Infof("  → Container %s can't unpack compressed files, retrying uncompressed", cInfo.ContainerID.ShortStr())
+			archive = newArchive(false)
+			err = cu.UpdateContainer(ctx, cInfo, archive, toRemovePaths, boiledSteps, info.hotReload)
+		}
+		if err == nil && info.signal != "" {
+			err = signalProcess(ctx, cu, cInfo, info.signal)
+		}
+		return err
+	})
+
+	// Handle the results in container order, so that what we report
+	// doesn't depend on which update finished first.
 	var lastUserBuildFailure error
-	for _, cInfo := range state.RunningContainers {
-		archive := build.TarArchiveForPaths(ctx, toArchive, filter)
-		err = cu.UpdateContainer(ctx, cInfo, archive,
-			build.PathMappingsToContainerPaths(toRemove), boiledSteps, hotReload)
+	var updatedContainer container.ID
+	for i, result := range results {
+		if !result.started {
+			continue
+		}
+
+		cInfo := info.state.RunningContainers[i]
+		err = result.err
+		lubad.recordContainerUpdateTime(ctx, cInfo, result.duration, err)
 		if err != nil {
 			if runFail, ok := build.MaybeRunStepFailure(err); ok {
-				// Keep running updates -- we want all containers to have the same files on them
+				// Keep checking updates -- we want all containers to have the same files on them
 				// even if the Runs don't succeed
 				lastUserBuildFailure = err
 				logger.Get(ctx).Infof("  → Failed to update container %s: run step %q failed with exit code: %d",
@@ -168,25 +299,448 @@ func (lubad *LiveUpdateBuildAndDeployer) buildAndDeploy(ctx context.Context, ps
 				continue
 			}
 
+			if ctx.Err() != nil {
+				// The update was interrupted because Tilt is shutting down.
+				return stoppedUpdateErr(ctx, info.state.RunningContainers, results)
+			}
+
 			// Something went wrong with this update and it's NOT the user's fault--
 			// likely a infrastructure error. Bail, and fall back to full build.
 			return err
-		} else {
-			logger.Get(ctx).Infof("  → Container %s updated!", cInfo.ContainerID.ShortStr())
-			if lastUserBuildFailure != nil {
-				// This build succeeded, but previously at least one failed due to user error.
-				// We may have inconsistent state--bail, and fall back to full build.
-				return fmt.Errorf("Failed to update container: container %s successfully updated, "+
-					"but last update failed with '%v'", cInfo.ContainerID.ShortStr(), lastUserBuildFailure)
-			}
+		}
+
+		logger.Get(ctx).Infof("  → Container %s updated!", cInfo.ContainerID.ShortStr())
+		if updatedContainer == "" {
+			updatedContainer = cInfo.ContainerID
 		}
 	}
+
+	// Batches run in order, so if we stopped early, the last container never started.
+	if ctx.Err() != nil && len(results) > 0 && !results[len(results)-1].started {
+		return stoppedUpdateErr(ctx, info.state.RunningContainers, results)
+	}
+
+	if lastUserBuildFailure != nil && updatedContainer != "" {
+		// At least one update succeeded, but at least one failed due to user error.
+		// We may have inconsistent state--bail, and fall back to full build.
+		return inconsistentUpdateError{updated: updatedContainer, lastFailure: lastUserBuildFailure}
+	}
 	if lastUserBuildFailure != nil {
 		return WrapDontFallBackError(lastUserBuildFailure)
 	}
 	return nil
 }
 
+// If we can't send the signal (e.g., the cluster doesn't let us exec into
+// pods), the error falls back to a full build, which replaces the container.
+func signalProcess(ctx context.Context, cu containerupdate.ContainerUpdater, cInfo store.ContainerInfo, signal string) error {
+	signaler, ok := cu.(containerupdate.ProcessSignaler)
+	if !ok {
+		return fmt.Errorf("can't send SIG%s to container %s: %T doesn't support signals",
+			signal, cInfo.ContainerID.ShortStr(), cu)
+	}
+
+	logger.Get(ctx).Infof("  → Sending SIG%s to main process of container %s", signal, cInfo.ContainerID.ShortStr())
+	return signaler.SignalProcess(ctx, cInfo, signal)
+}
+
+// Builds each archive at most once, so that we don't re-read every file for
+// every container we update. Failed builds aren't cached, so they can be retried.
+//
+// Archives bigger than maxBytes aren't cached either, so that a huge sync
+// doesn't sit in memory. Each container reads its own copy instead.
+type archiveCache struct {
+	mu       sync.Mutex
+	build    func(gzip bool) io.Reader
+	maxBytes int64
+	archives map[bool][]byte
+	tooBig   map[bool]bool
+}
+
+const defaultMaxCachedArchiveBytes = 64 * 1024 * 1024
+
+func newArchiveCache(build func(gzip bool) io.Reader) *archiveCache {
+	return &archiveCache{
+		build:    build,
+		maxBytes: defaultMaxCachedArchiveBytes,
+		archives: make(map[bool][]byte),
+		tooBig:   make(map[bool]bool),
+	}
+}
+
+func (c *archiveCache) get(gzip bool) io.Reader {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if archive, ok := c.archives[gzip]; ok {
+		return bytes.NewReader(archive)
+	}
+	if c.tooBig[gzip] {
+		return c.build(gzip)
+	}
+
+	r := c.build(gzip)
+	archive, err := io.ReadAll(io.LimitReader(r, c.maxBytes+1))
+	if err != nil {
+		return errReader{err: err}
+	}
+	if int64(len(archive)) > c.maxBytes {
+		// Stream the rest of this one to the caller.
+		c.tooBig[gzip] = true
+		return io.MultiReader(bytes.NewReader(archive), r)
+	}
+	c.archives[gzip] = archive
+	return bytes.NewReader(archive)
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
+
+// Some containers were updated, but a run step failed on others, so the
+// containers may not have the same state.
+type inconsistentUpdateError struct {
+	updated     container.ID
+	lastFailure error
+}
+
+func (e inconsistentUpdateError) Error() string {
+	return fmt.Sprintf("Failed to update container: container %s successfully updated, "+
+		"but last update failed with '%v'", e.updated.ShortStr(), e.lastFailure)
+}
+
+func isInconsistentUpdateError(err error) bool {
+	_, ok := err.(inconsistentUpdateError)
+	return ok
+}
+
+// Runs the local_pre_sync cmds, then adds the files they wrote under a sync
+// source to the files we sync, so that their output gets to the container.
+//
+// To find what they wrote, we walk every sync source and compare mod times, so
+// this costs a stat of every file under the syncs that the image's ignores
+// don't exclude. Images that don't use local_pre_sync don't pay for it.
+func (lubad *LiveUpdateBuildAndDeployer) runLocalPreSync(ctx context.Context, info *liveUpdInfo) error {
+	luInfo := info.iTarget.LiveUpdateInfo()
+	cmds := luInfo.LocalPreSyncCmds()
+	if len(cmds) == 0 {
+		return nil
+	}
+
+	start := lubad.clock.Now()
+	err := runLocalPreSyncCmds(ctx, cmds)
+	if err != nil {
+		return err
+	}
+
+	syncs := luInfo.SyncSteps()
+	written, err := filesModifiedSince(syncs, ignore.CreateBuildContextFilter(info.iTarget), start)
+	if err != nil {
+		return errors.Wrap(err, "local_pre_sync")
+	}
+	mappings, _, err := build.FilesToPathMappings(written, syncs)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(info.changedFiles))
+	for _, pm := range info.changedFiles {
+		seen[pm.LocalPath] = true
+	}
+	for _, pm := range mappings {
+		if !seen[pm.LocalPath] {
+			info.changedFiles = append(info.changedFiles, pm)
+			info.localPreSyncFiles = append(info.localPreSyncFiles, pm.LocalPath)
+		}
+	}
+	return nil
+}
+
+// The files under the sync sources modified at or after the given time.
+// Walks the whole source tree, except for the directories the filter ignores.
+func filesModifiedSince(syncs []model.Sync, filter model.PathMatcher, since time.Time) ([]string, error) {
+	var result []string
+	for _, sync := range syncs {
+		err := filepath.WalkDir(sync.LocalPath, func(path string, d fs.DirEntry, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				skip, err := filter.MatchesEntireDir(path)
+				if err != nil {
+					return err
+				}
+				if skip {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			ignored, err := filter.Matches(path)
+			if err != nil || ignored {
+				return err
+			}
+			info, err := d.Info()
+			if os.IsNotExist(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if !info.ModTime().Before(since) {
+				result = append(result, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func runLocalPreSyncCmds(ctx context.Context, cmds []model.Cmd) error {
+	l := logger.Get(ctx)
+	for _, cmd := range cmds {
+		l.Infof("Running local_pre_sync cmd %q", cmd.String())
+
+		c := exec.CommandContext(ctx, cmd.Argv[0], cmd.Argv[1:]...)
+		c.Dir = cmd.Dir
+		c.Env = append(logger.DefaultEnv(ctx), cmd.Env...)
+		w := l.Writer(logger.InfoLvl)
+		c.Stdout = w
+		c.Stderr = w
+
+		err := c.Run()
+		if err != nil {
+			return DontFallBackErrorf("local_pre_sync cmd %q failed: %v", cmd.String(), err)
+		}
+	}
+	return nil
+}
+
+// Tilt is shutting down, so we stopped updating containers partway through.
+// Tell the user which containers don't have the new files.
+func stoppedUpdateErr(ctx context.Context, cInfos []store.ContainerInfo, results []containerUpdateResult) error {
+	var notUpdated []container.ID
+	for i, result := range results {
+		if !result.started || result.err != nil {
+			notUpdated = append(notUpdated, cInfos[i].ContainerID)
+		}
+	}
+	if len(notUpdated) > 0 {
+		logger.Get(ctx).Infof("  → Stopped before updating container(s): %s", container.ShortStrs(notUpdated))
+	}
+	return errors.Wrap(ctx.Err(), "live update stopped")
+}
+
+type containerUpdateResult struct {
+	// False if we never tried to update this container (because earlier
+	// updates meant we'd fall back to a full build anyway).
+	started  bool
+	duration time.Duration
+	err      error
+}
+
+// Run the update on each container, in batches of maxParallelUpdates,
+// so that the pod's containers that run this image don't wait on each other's copies.
+//
+// After each batch, if we already know we're going to fall back to a full build,
+// or if Tilt is shutting down, we don't start any more.
+//
+// Results are returned in the same order as the containers.
+func (lubad *LiveUpdateBuildAndDeployer) updateContainers(ctx context.Context, cInfos []store.ContainerInfo,
+	update func(cInfo store.ContainerInfo) error) []containerUpdateResult {
+	results := make([]containerUpdateResult, len(cInfos))
+	for start := 0; start < len(cInfos); start += lubad.maxParallelUpdates {
+		if ctx.Err() != nil {
+			break
+		}
+
+		end := start + lubad.maxParallelUpdates
+		if end > len(cInfos) {
+			end = len(cInfos)
+		}
+
+		var wg sync.WaitGroup
+		for i := start; i < end; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				startTime := lubad.clock.Now()
+				err := update(cInfos[i])
+				results[i] = containerUpdateResult{
+					started:  true,
+					duration: lubad.clock.Now().Sub(startTime),
+					err:      err,
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		if willFallBack(results[:end]) {
+			break
+		}
+	}
+	return results
+}
+
+// Whether these container updates mean we'll fall back to a full build: either
+// one failed with an error that isn't the user's fault, or some succeeded while
+// others failed a run step (so the containers are inconsistent).
+func willFallBack(results []containerUpdateResult) bool {
+	anySuccess := false
+	anyRunStepFailure := false
+	for _, result := range results {
+		if result.err == nil {
+			anySuccess = true
+		} else if build.IsRunStepFailure(result.err) {
+			anyRunStepFailure = true
+		} else {
+			return true
+		}
+	}
+	return anySuccess && anyRunStepFailure
+}
+
+// When a Live Update has multiple syncs, tell the user which sync claimed the file.
+func syncRuleSuffix(syncIndexes map[string]int, pm build.PathMapping) string {
+	i, ok := syncIndexes[pm.LocalPath]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(" (via sync #%d)", i+1)
+}
+
+// Record how long it took to copy files to (and run steps in) a single container.
+// Failed updates are recorded too, so that we can see the time spent before the failure.
+func (lubad *LiveUpdateBuildAndDeployer) recordContainerUpdateTime(ctx context.Context, cInfo store.ContainerInfo, dur time.Duration, err error) {
+	analytics.Get(ctx).Timer("build.container.update", dur, map[string]string{
+		"hasError": fmt.Sprintf("%t", err != nil),
+	})
+	logger.Get(ctx).Debugf("  → Container %s update took %.2fs", cInfo.ContainerID.ShortStr(), dur.Seconds())
+}
+
+// If a live update would copy a huge number of files (e.g., because someone
+// edited a generated directory), it's usually faster and safer to
+// do an image build. Both limits are ignored when unset.
+func checkLiveUpdateLimits(info liveUpdInfo, settings model.UpdateSettings) error {
+	maxFiles := settings.LiveUpdateMaxFiles()
+	if maxFiles > 0 && len(info.changedFiles) > maxFiles {
+		return RedirectToNextBuilderInfof(
+			"Too many files to Live Update %s (%d files, max %d)",
+			info.iTarget.ID(), len(info.changedFiles), maxFiles).
+			WithFallBackReason(model.FallBackReasonLimit)
+	}
+
+	maxBytes := settings.LiveUpdateMaxBytes()
+	if maxBytes <= 0 {
+		return nil
+	}
+
+	var totalBytes int64
+	for _, pm := range info.changedFiles {
+		size, err := localPathSize(pm.LocalPath)
+		if err != nil {
+			return errors.Wrap(err, "checking Live Update size")
+		}
+		totalBytes += size
+		if totalBytes > maxBytes {
+			return RedirectToNextBuilderInfof(
+				"Too many bytes to Live Update %s (more than %d bytes)",
+				info.iTarget.ID(), maxBytes).
+				WithFallBackReason(model.FallBackReasonLimit)
+		}
+	}
+	return nil
+}
+
+// When two changed files would be synced to the same container path, the
+// last one copied silently wins. That usually means the sync rules overlap
+// in a way the user didn't intend, so call it out.
+func checkSyncCollisions(ctx context.Context, info liveUpdInfo, settings model.UpdateSettings) error {
+	collisions := build.CollidingContainerPaths(info.changedFiles)
+	if len(collisions) == 0 {
+		return nil
+	}
+
+	var lines []string
+	for _, c := range collisions {
+		lines = append(lines, fmt.Sprintf("- %s", c))
+	}
+	msg := fmt.Sprintf("Multiple files sync to the same container path in %s:\n%s",
+		info.iTarget.ID(), strings.Join(lines, "\n"))
+
+	if settings.LiveUpdateStrictSyncs() {
+		return DontFallBackErrorf("%s\n(live_update_strict_syncs is set)", msg)
+	}
+
+	logger.Get(ctx).Warnf("%s", msg)
+	return nil
+}
+
+// Gzipping costs CPU on both ends, so we only do it when there are
+// enough bytes to copy that the smaller transfer pays for it.
+func shouldGzip(info liveUpdInfo, settings model.UpdateSettings) (bool, error) {
+	minBytes := settings.LiveUpdateCompressMinBytes()
+	if minBytes <= 0 {
+		return false, nil
+	}
+
+	var totalBytes int64
+	for _, pm := range info.changedFiles {
+		size, err := localPathSize(pm.LocalPath)
+		if err != nil {
+			return false, errors.Wrap(err, "checking Live Update size")
+		}
+		totalBytes += size
+		if totalBytes >= minBytes {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// The number of bytes of regular files at this path. Files that
+// no longer exist locally will be deleted, not copied, so they count as zero.
+func localPathSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	return size, err
+}
+
+// True if the user paused live updates on a manifest that uses this image.
+func isLiveUpdatePaused(state store.EngineState, id model.TargetID) bool {
+	for _, mn := range state.ManifestNamesForTargetID(id) {
+		ms, ok := state.ManifestState(mn)
+		if ok && ms.LiveUpdatePaused {
+			return true
+		}
+	}
+	return false
+}
+
 // liveUpdateInfoForStateTree validates the state tree for LiveUpdate and returns
 // all the info we need to execute the update.
 func liveUpdateInfoForStateTree(stateTree liveUpdateStateTree) (liveUpdInfo, error) {
@@ -198,6 +752,7 @@ func liveUpdateInfoForStateTree(stateTree liveUpdateStateTree) (liveUpdInfo, err
 	var fileMappings []build.PathMapping
 	var runs []model.Run
 	var hotReload bool
+	var signal string
 
 	if luInfo := iTarget.LiveUpdateInfo(); !luInfo.Empty() {
 		var pathsMatchingNoSync []string
@@ -208,22 +763,25 @@ func liveUpdateInfoForStateTree(stateTree liveUpdateStateTree) (liveUpdInfo, err
 		if len(pathsMatchingNoSync) > 0 {
 			return liveUpdInfo{}, RedirectToNextBuilderInfof(
 				"Found file(s) not matching any sync for %s (files: %s)", iTarget.ID(),
-				ospath.FormatFileChangeList(pathsMatchingNoSync))
+				ospath.FormatFileChangeList(pathsMatchingNoSync)).
+				WithFallBackReason(model.FallBackReasonNoMatch)
 		}
 
 		// If any changed files match a FallBackOn file, fall back to next BuildAndDeployer
-		anyMatch, file, err := luInfo.FallBackOnFiles().AnyMatch(filesChanged)
+		anyMatch, file, err := luInfo.FallBackOnFiles().AnyGlobMatch(filesChanged)
 		if err != nil {
 			return liveUpdInfo{}, err
 		}
 		if anyMatch {
 			prettyFile := ospath.FileDisplayName(iTarget.LocalPaths(), file)
 			return liveUpdInfo{}, RedirectToNextBuilderInfof(
-				"Detected change to fall_back_on file %q", prettyFile)
+				"Detected change to fall_back_on file %q", prettyFile).
+				WithFallBackReason(model.FallBackReasonStopPath)
 		}
 
 		runs = luInfo.RunSteps()
 		hotReload = !luInfo.ShouldRestart()
+		signal = luInfo.SignalOnUpdate()
 	} else {
 		// We should have validated this when generating the LiveUpdateStateTrees, but double check!
 		panic(fmt.Sprintf("did not find Live Update info on target %s, "+
@@ -241,22 +799,6 @@ func liveUpdateInfoForStateTree(stateTree liveUpdateStateTree) (liveUpdInfo, err
 		changedFiles: fileMappings,
 		runs:         runs,
 		hotReload:    hotReload,
+		signal:       signal,
 	}, nil
 }
-
-func (lubad *LiveUpdateBuildAndDeployer) containerUpdaterForSpecs(specs []model.TargetSpec) containerupdate.ContainerUpdater {
-	isDC := len(model.ExtractDockerComposeTargets(specs)) > 0
-	if isDC || lubad.updMode == UpdateModeContainer {
-		return lubad.dcu
-	}
-
-	if lubad.updMode == UpdateModeKubectlExec {
-		return lubad.ecu
-	}
-
-	if lubad.dcu.WillBuildToKubeContext(lubad.kubeContext) {
-		return lubad.dcu
-	}
-
-	return lubad.ecu
-}
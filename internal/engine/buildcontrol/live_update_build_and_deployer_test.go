@@ -2,20 +2,30 @@ package buildcontrol
 
 import (
 	"archive/tar"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tilt-dev/wmclient/pkg/analytics"
 
 	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/containerupdate"
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/manifestbuilder"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -48,7 +58,7 @@ func TestBuildAndDeployBoilsSteps(t *testing.T) {
 		model.Run{Cmd: model.ToUnixCmd("pip install"), Triggers: f.newPathSet("requirements.txt")},
 	}
 
-	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, model.ImageTarget{}, TestBuildState, []build.PathMapping{packageJson}, runs, false)
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: TestBuildState, changedFiles: []build.PathMapping{packageJson}, runs: runs})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -80,7 +90,7 @@ func TestUpdateInContainerArchivesFilesToCopyAndGetsFilesToRemove(t *testing.T)
 		build.PathMapping{LocalPath: f.JoinPath("does-not-exist"), ContainerPath: "/src/does-not-exist"},
 	}
 
-	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, model.ImageTarget{}, TestBuildState, paths, nil, false)
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: TestBuildState, changedFiles: paths})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -107,19 +117,94 @@ func TestDontFallBackOnUserError(t *testing.T) {
 
 	f.cu.SetUpdateErr(build.RunStepFailure{ExitCode: 12345})
 
-	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, model.ImageTarget{}, TestBuildState, nil, nil, false)
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: TestBuildState})
 	if assert.NotNil(t, err) {
 		assert.IsType(t, DontFallBackError{}, err)
 	}
 }
 
+func TestRetryOnTarWriteError(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	f.cu.UpdateErrs = []error{build.TarWriteError{Err: fmt.Errorf("read foo.py: input/output error")}, nil}
+
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: TestBuildState})
+	require.NoError(t, err)
+	assert.Len(t, f.cu.Calls, 2, "should retry UpdateContainer after a tar write error")
+}
+
+func TestRetryOnTarWriteErrorOnlyOnce(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	tarErr := build.TarWriteError{Err: fmt.Errorf("read foo.py: input/output error")}
+	f.cu.UpdateErrs = []error{tarErr, tarErr}
+
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: TestBuildState})
+	require.Error(t, err)
+	assert.True(t, build.IsTarWriteError(err))
+	assert.False(t, IsDontFallBackError(err))
+	assert.Len(t, f.cu.Calls, 2)
+}
+
+func TestRetryUncompressedWhenGzipUnsupported(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	f.cu.UpdateErrs = []error{containerupdate.GzipUnsupportedError{Err: fmt.Errorf("tar: invalid option -- 'z'")}, nil}
+
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: TestBuildState, gzip: true})
+	require.NoError(t, err)
+	if assert.Len(t, f.cu.Calls, 2, "should retry UpdateContainer uncompressed") {
+		assert.True(t, isGzipped(t, f.cu.Calls[0].Archive))
+		assert.False(t, isGzipped(t, f.cu.Calls[1].Archive))
+	}
+}
+
+func TestSignalProcessAfterUpdate(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: TestBuildState, hotReload: true, signal: "HUP"})
+	require.NoError(t, err)
+	assert.Len(t, f.cu.Calls, 1)
+	if assert.Len(t, f.cu.SignalCalls, 1) {
+		assert.Equal(t, "HUP", f.cu.SignalCalls[0].Signal)
+		assert.Equal(t, TestContainerInfo, f.cu.SignalCalls[0].ContainerInfo)
+	}
+}
+
+func TestSignalProcessErrorFallsBack(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	f.cu.SignalErrs = []error{fmt.Errorf("pods \"foo\" is forbidden")}
+
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: TestBuildState, hotReload: true, signal: "HUP"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "forbidden")
+	assert.False(t, IsDontFallBackError(err))
+}
+
+func TestNoSignalWhenUpdateFails(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	f.cu.SetUpdateErr(build.RunStepFailure{ExitCode: 1})
+
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: TestBuildState, hotReload: true, signal: "HUP"})
+	require.Error(t, err)
+	assert.Empty(t, f.cu.SignalCalls)
+}
+
 func TestUpdateContainerWithHotReload(t *testing.T) {
 	f := newFixture(t)
 	defer f.teardown()
 
 	expectedHotReloads := []bool{true, true, false, true}
 	for _, hotReload := range expectedHotReloads {
-		err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, model.ImageTarget{}, TestBuildState, nil, nil, hotReload)
+		err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: TestBuildState, hotReload: hotReload})
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -165,7 +250,7 @@ func TestUpdateMultipleRunningContainers(t *testing.T) {
 	cmd := model.ToUnixCmd("./foo.sh bar")
 	runs := []model.Run{model.ToRun(cmd)}
 
-	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, model.ImageTarget{}, state, paths, runs, true)
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: state, changedFiles: paths, runs: runs, hotReload: true})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -184,6 +269,35 @@ func TestUpdateMultipleRunningContainers(t *testing.T) {
 	}
 }
 
+func TestRecordsUpdateTimePerContainer(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	cInfo1 := store.ContainerInfo{PodID: "mypod", ContainerID: "cid1", ContainerName: "container1", Namespace: "ns-foo"}
+	cInfo2 := store.ContainerInfo{PodID: "mypod", ContainerID: "cid2", ContainerName: "container2", Namespace: "ns-foo"}
+	state := store.BuildState{
+		LastResult:        alreadyBuilt,
+		FilesChangedSet:   map[string]bool{"foo.py": true},
+		RunningContainers: []store.ContainerInfo{cInfo1, cInfo2},
+	}
+
+	paths := []build.PathMapping{
+		build.PathMapping{LocalPath: f.JoinPath("does-not-exist"), ContainerPath: "/src/does-not-exist"},
+	}
+
+	f.cu.UpdateErrs = []error{nil, fmt.Errorf("oh no")}
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: state, changedFiles: paths})
+	require.Error(t, err)
+
+	var hasError []string
+	for _, timer := range f.ma.Timers {
+		if timer.Name == "build.container.update" {
+			hasError = append(hasError, timer.Tags["hasError"])
+		}
+	}
+	assert.Equal(t, []string{"false", "true"}, hasError)
+}
+
 func TestErrorStopsSubsequentContainerUpdates(t *testing.T) {
 	f := newFixture(t)
 	defer f.teardown()
@@ -209,12 +323,85 @@ func TestErrorStopsSubsequentContainerUpdates(t *testing.T) {
 	}
 
 	f.cu.SetUpdateErr(fmt.Errorf("👀"))
-	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, model.ImageTarget{}, state, nil, nil, false)
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: state})
 	require.NotNil(t, err)
 	assert.Contains(t, "👀", err.Error())
 	require.Len(t, f.cu.Calls, 1, "should only call UpdateContainer once (error should stop subsequent calls)")
 }
 
+func TestUpdateMultipleContainersInParallel(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	cInfos := threeContainerInfos()
+	state := store.BuildState{
+		LastResult:        alreadyBuilt,
+		FilesChangedSet:   map[string]bool{"foo.py": true},
+		RunningContainers: cInfos,
+	}
+
+	// Each update blocks until all of them have started, so this
+	// only finishes if they run at the same time.
+	cu := newBarrierContainerUpdater(len(cInfos))
+	cu.errs[cInfos[1].ContainerID] = rsf
+	f.lubad.maxParallelUpdates = len(cInfos)
+
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, cu, liveUpdInfo{state: state, hotReload: true})
+	require.Error(t, err)
+
+	// Containers 0 and 2 succeeded, but container 1's run step failed.
+	assert.Contains(t, err.Error(), "container cid0 successfully updated, but last update failed with")
+	assert.False(t, IsDontFallBackError(err))
+}
+
+func TestUpdateMultipleContainersStopsWhenCancelled(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	cInfos := threeContainerInfos()
+	state := store.BuildState{
+		LastResult:        alreadyBuilt,
+		FilesChangedSet:   map[string]bool{"foo.py": true},
+		RunningContainers: cInfos,
+	}
+
+	out := bytes.NewBuffer(nil)
+	ctx, cancel := context.WithCancel(logger.WithLogger(f.ctx, logger.NewTestLogger(out)))
+	defer cancel()
+
+	// Tilt shuts down while the first container is updating.
+	cu := &cancelingContainerUpdater{cancel: cancel}
+	err := f.lubad.buildAndDeploy(ctx, f.ps, cu, liveUpdInfo{state: state, hotReload: true})
+	require.Error(t, err)
+	assert.True(t, IsFatalError(err))
+	assert.Equal(t, []container.ID{"cid0"}, cu.calls)
+	assert.Contains(t, out.String(), "Stopped before updating container(s): cid1, cid2")
+}
+
+func TestUpdateInterruptedByCancelDoesNotFallBack(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	cInfos := threeContainerInfos()
+	state := store.BuildState{
+		LastResult:        alreadyBuilt,
+		FilesChangedSet:   map[string]bool{"foo.py": true},
+		RunningContainers: cInfos,
+	}
+
+	out := bytes.NewBuffer(nil)
+	ctx, cancel := context.WithCancel(logger.WithLogger(f.ctx, logger.NewTestLogger(out)))
+	defer cancel()
+
+	// The in-flight exec fails because its context was cancelled.
+	cu := &cancelingContainerUpdater{cancel: cancel, err: fmt.Errorf("exec interrupted")}
+	err := f.lubad.buildAndDeploy(ctx, f.ps, cu, liveUpdInfo{state: state, hotReload: true})
+	require.Error(t, err)
+	assert.True(t, IsFatalError(err))
+	assert.False(t, ShouldFallBackForErr(err))
+	assert.Contains(t, out.String(), "Stopped before updating container(s): cid0, cid1, cid2")
+}
+
 func TestUpdateMultipleContainersWithSameTarArchive(t *testing.T) {
 	f := newFixture(t)
 	defer f.teardown()
@@ -252,7 +439,7 @@ func TestUpdateMultipleContainersWithSameTarArchive(t *testing.T) {
 		expectFile("src/planets/earth", "world"),
 	}
 
-	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, model.ImageTarget{}, state, paths, nil, true)
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: state, changedFiles: paths, hotReload: true})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -303,7 +490,7 @@ func TestUpdateMultipleContainersWithSameTarArchiveOnRunStepFailure(t *testing.T
 	}
 
 	f.cu.UpdateErrs = []error{rsf, rsf}
-	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, model.ImageTarget{}, state, paths, nil, true)
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: state, changedFiles: paths, hotReload: true})
 	require.NotNil(t, err)
 	assert.Contains(t, err.Error(), "Run step \"omgwtfbbq\" failed with exit code: 123")
 
@@ -315,6 +502,68 @@ func TestUpdateMultipleContainersWithSameTarArchiveOnRunStepFailure(t *testing.T
 	}
 }
 
+func TestArchiveCacheBuildsOnce(t *testing.T) {
+	builds := 0
+	cache := newArchiveCache(func(gzip bool) io.Reader {
+		builds++
+		return bytes.NewBufferString(fmt.Sprintf("gzip=%t", gzip))
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			contents, err := io.ReadAll(cache.get(false))
+			assert.NoError(t, err)
+			assert.Equal(t, "gzip=false", string(contents))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, builds)
+
+	contents, err := io.ReadAll(cache.get(true))
+	require.NoError(t, err)
+	assert.Equal(t, "gzip=true", string(contents))
+	assert.Equal(t, 2, builds)
+}
+
+func TestArchiveCacheRetriesFailedBuild(t *testing.T) {
+	builds := 0
+	cache := newArchiveCache(func(gzip bool) io.Reader {
+		builds++
+		if builds == 1 {
+			return errReader{err: build.TarWriteError{Err: fmt.Errorf("disk on fire")}}
+		}
+		return bytes.NewBufferString("ok")
+	})
+
+	_, err := io.ReadAll(cache.get(false))
+	assert.True(t, build.IsTarWriteError(err))
+
+	contents, err := io.ReadAll(cache.get(false))
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(contents))
+	assert.Equal(t, 2, builds)
+}
+
+func TestArchiveCacheDoesNotKeepBigArchives(t *testing.T) {
+	builds := 0
+	cache := newArchiveCache(func(gzip bool) io.Reader {
+		builds++
+		return bytes.NewBufferString("0123456789")
+	})
+	cache.maxBytes = 4
+
+	for i := 0; i < 2; i++ {
+		contents, err := io.ReadAll(cache.get(false))
+		require.NoError(t, err)
+		assert.Equal(t, "0123456789", string(contents))
+	}
+	assert.Equal(t, 2, builds)
+	assert.Empty(t, cache.archives)
+}
+
 func TestSkipLiveUpdateIfForceUpdate(t *testing.T) {
 	f := newFixture(t)
 	defer f.teardown()
@@ -341,10 +590,432 @@ func TestSkipLiveUpdateIfForceUpdate(t *testing.T) {
 	assert.Contains(t, err.Error(), "Force update", "expected error contents not found")
 }
 
+func TestLiveUpdateLimitsUnsetMeansUnlimited(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	f.WriteFile("a.txt", "aaaa")
+	f.WriteFile("b.txt", "bbbb")
+	info := liveUpdInfo{changedFiles: []build.PathMapping{
+		{LocalPath: f.JoinPath("a.txt"), ContainerPath: "/src/a.txt"},
+		{LocalPath: f.JoinPath("b.txt"), ContainerPath: "/src/b.txt"},
+	}}
+
+	assert.NoError(t, checkLiveUpdateLimits(info, model.UpdateSettings{}))
+}
+
+func TestLiveUpdateMaxFilesFallsBack(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	// The limit check should happen before we ever touch a container updater.
+	m := NewSanchoLiveUpdateManifest(f)
+	f.WriteFile("a.txt", "aaaa")
+	f.WriteFile("b.txt", "bbbb")
+	f.st.WithState(func(state *store.EngineState) {
+		state.UpdateSettings = state.UpdateSettings.WithLiveUpdateMaxFiles(1)
+	})
+
+	state := store.BuildState{
+		LastResult:        alreadyBuilt,
+		RunningContainers: []store.ContainerInfo{TestContainerInfo},
+		FilesChangedSet:   map[string]bool{f.JoinPath("a.txt"): true, f.JoinPath("b.txt"): true},
+	}
+	stateSet := store.BuildStateSet{m.ImageTargetAt(0).ID(): state}
+
+	_, err := f.lubad.BuildAndDeploy(f.ctx, f.st, m.TargetSpecs(), stateSet)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Too many files to Live Update")
+	assert.True(t, ShouldFallBackForErr(err))
+	if assert.IsType(t, RedirectToNextBuilder{}, err) {
+		assert.Equal(t, model.FallBackReasonLimit, err.(RedirectToNextBuilder).FallBackReason)
+	}
+	assert.Len(t, f.cu.Calls, 0)
+}
+
+func TestLiveUpdateSkipRuns(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	m := NewSanchoLiveUpdateManifest(f)
+	f.WriteFile("a.txt", "aaaa")
+	f.st.WithState(func(state *store.EngineState) {
+		state.UpdateSettings = state.UpdateSettings.WithLiveUpdateSkipRuns(true)
+	})
+
+	state := store.BuildState{
+		LastResult:        alreadyBuilt,
+		RunningContainers: []store.ContainerInfo{TestContainerInfo},
+		FilesChangedSet:   map[string]bool{f.JoinPath("a.txt"): true},
+	}
+	stateSet := store.BuildStateSet{m.ImageTargetAt(0).ID(): state}
+
+	_, err := f.lubad.BuildAndDeploy(f.ctx, f.st, m.TargetSpecs(), stateSet)
+	require.NoError(t, err)
+	if assert.Len(t, f.cu.Calls, 1) {
+		assert.NotNil(t, f.cu.Calls[0].Archive)
+		assert.Empty(t, f.cu.Calls[0].Cmds)
+	}
+}
+
+func TestLiveUpdatePausedSkipsSync(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	m := NewSanchoLiveUpdateManifest(f)
+	f.pauseLiveUpdate(m)
+	f.WriteFile("a.txt", "aaaa")
+
+	iTargetID := m.ImageTargetAt(0).ID()
+	state := store.BuildState{
+		LastResult:        alreadyBuilt,
+		RunningContainers: []store.ContainerInfo{TestContainerInfo},
+		FilesChangedSet:   map[string]bool{f.JoinPath("a.txt"): true},
+	}
+	stateSet := store.BuildStateSet{iTargetID: state}
+
+	out := bytes.NewBuffer(nil)
+	ctx := logger.WithLogger(f.ctx, logger.NewTestLogger(out))
+	resultSet, err := f.lubad.BuildAndDeploy(ctx, f.st, m.TargetSpecs(), stateSet)
+	require.NoError(t, err)
+	assert.Len(t, f.cu.Calls, 0)
+	assert.Contains(t, out.String(), "Live updates paused for gcr.io/some-project-162817/sancho. Skipping sync of 1 changed file(s)")
+
+	// We report the skipped files, so that we can sync them on resume.
+	result := resultSet[iTargetID].(store.LiveUpdateBuildResult)
+	assert.Equal(t, []string{f.JoinPath("a.txt")}, result.PausedFiles)
+}
+
+func TestLiveUpdatePausedStillFallsBack(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	syncs := []model.LiveUpdateSyncStep{{Source: f.Path(), Dest: "/src"}}
+	lu := assembleLiveUpdate(syncs, nil, false, []string{"Dockerfile"}, f)
+	m := manifestbuilder.New(f, "sancho").
+		WithK8sYAML(SanchoYAML).
+		WithImageTarget(imageTargetWithLiveUpdate(NewSanchoDockerBuildImageTarget(f), lu)).
+		Build()
+	f.pauseLiveUpdate(m)
+
+	state := store.BuildState{
+		LastResult:        alreadyBuilt,
+		RunningContainers: []store.ContainerInfo{TestContainerInfo},
+		FilesChangedSet:   map[string]bool{f.WriteFile("Dockerfile", "FROM alpine"): true},
+	}
+	stateSet := store.BuildStateSet{m.ImageTargetAt(0).ID(): state}
+
+	_, err := f.lubad.BuildAndDeploy(f.ctx, f.st, m.TargetSpecs(), stateSet)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Detected change to fall_back_on file")
+	assert.True(t, ShouldFallBackForErr(err))
+}
+
+func TestLiveUpdateMaxBytes(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	f.WriteFile("a.txt", "aaaa")
+	f.WriteFile("dir/b.txt", "bbbb")
+	info := liveUpdInfo{changedFiles: []build.PathMapping{
+		{LocalPath: f.JoinPath("a.txt"), ContainerPath: "/src/a.txt"},
+		{LocalPath: f.JoinPath("dir"), ContainerPath: "/src/dir"},
+		{LocalPath: f.JoinPath("deleted.txt"), ContainerPath: "/src/deleted.txt"},
+	}}
+
+	err := checkLiveUpdateLimits(info, model.UpdateSettings{}.WithLiveUpdateMaxBytes(8))
+	assert.NoError(t, err)
+
+	err = checkLiveUpdateLimits(info, model.UpdateSettings{}.WithLiveUpdateMaxBytes(7))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Too many bytes to Live Update")
+		assert.Equal(t, model.FallBackReasonLimit, err.(RedirectToNextBuilder).FallBackReason)
+	}
+}
+
+func TestLiveUpdateSyncCollisionWarns(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	m, stateSet := f.collidingSyncsManifest()
+
+	out := bytes.NewBuffer(nil)
+	ctx := logger.WithLogger(f.ctx, logger.NewTestLogger(out))
+	_, err := f.lubad.BuildAndDeploy(ctx, f.st, m.TargetSpecs(), stateSet)
+	require.NoError(t, err)
+	assert.Len(t, f.cu.Calls, 1)
+	assert.Contains(t, out.String(), "Multiple files sync to the same container path")
+	assert.Contains(t, out.String(), fmt.Sprintf("'/app/x.txt' (matched local paths: '%s', '%s')",
+		f.JoinPath("bar", "x.txt"), f.JoinPath("foo", "x.txt")))
+}
+
+func TestLiveUpdateSyncCollisionFailsWhenStrict(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	m, stateSet := f.collidingSyncsManifest()
+	f.st.WithState(func(state *store.EngineState) {
+		state.UpdateSettings = state.UpdateSettings.WithLiveUpdateStrictSyncs(true)
+	})
+
+	_, err := f.lubad.BuildAndDeploy(f.ctx, f.st, m.TargetSpecs(), stateSet)
+	if assert.Error(t, err) {
+		assert.True(t, IsDontFallBackError(err), "expected DontFallBackError, got: %v", err)
+		assert.Contains(t, err.Error(), "Multiple files sync to the same container path")
+	}
+	assert.Empty(t, f.cu.Calls)
+}
+
+func TestUpdateTimesUseClock(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	f.lubad.clock = &steppingClock{now: time.Unix(1551202573, 0), step: time.Second}
+
+	cInfo1 := store.ContainerInfo{PodID: "mypod", ContainerID: "cid1", ContainerName: "container1", Namespace: "ns-foo"}
+	cInfo2 := store.ContainerInfo{PodID: "mypod", ContainerID: "cid2", ContainerName: "container2", Namespace: "ns-foo"}
+	state := store.BuildState{
+		LastResult:        alreadyBuilt,
+		FilesChangedSet:   map[string]bool{"foo.py": true},
+		RunningContainers: []store.ContainerInfo{cInfo1, cInfo2},
+	}
+
+	err := f.lubad.buildAndDeploy(f.ctx, f.ps, f.cu, liveUpdInfo{state: state})
+	require.NoError(t, err)
+
+	durations := make(map[string][]time.Duration)
+	for _, timer := range f.ma.Timers {
+		durations[timer.Name] = append(durations[timer.Name], timer.Dur)
+	}
+	// Each call to Now() moves the clock forward a second.
+	assert.Equal(t, []time.Duration{time.Second, time.Second}, durations["build.container.update"])
+	assert.Equal(t, []time.Duration{5 * time.Second}, durations["build.container"])
+}
+
+func TestLiveUpdateToleratePartialFailure(t *testing.T) {
+	for _, tolerate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("tolerate=%t", tolerate), func(t *testing.T) {
+			f := newFixture(t)
+			defer f.teardown()
+
+			m := NewSanchoLiveUpdateManifest(f)
+			f.st.WithState(func(state *store.EngineState) {
+				state.UpdateSettings = state.UpdateSettings.WithLiveUpdateToleratePartialFailure(tolerate)
+			})
+
+			cInfo1 := store.ContainerInfo{PodID: "mypod", ContainerID: "cid1", ContainerName: "container1", Namespace: "ns-foo"}
+			cInfo2 := store.ContainerInfo{PodID: "mypod", ContainerID: "cid2", ContainerName: "container2", Namespace: "ns-foo"}
+			state := store.BuildState{
+				LastResult:        alreadyBuilt,
+				RunningContainers: []store.ContainerInfo{cInfo1, cInfo2},
+				FilesChangedSet:   map[string]bool{f.JoinPath("a.txt"): true},
+			}
+			stateSet := store.BuildStateSet{m.ImageTargetAt(0).ID(): state}
+
+			// The run step fails on the second container only.
+			f.cu.UpdateErrs = []error{nil, rsf}
+			_, err := f.lubad.BuildAndDeploy(f.ctx, f.st, m.TargetSpecs(), stateSet)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "container cid1 successfully updated, but last update failed with")
+				assert.Equal(t, tolerate, IsDontFallBackError(err))
+			}
+			assert.Len(t, f.cu.Calls, 2)
+		})
+	}
+}
+
+func TestLocalPreSyncCmd(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		cmd         string
+		expectedErr string
+		expectedLog string
+	}{
+		{name: "success", cmd: "echo compiled > gen.txt && echo compiled", expectedLog: "compiled"},
+		{name: "failure", cmd: "echo oops && exit 3", expectedErr: `local_pre_sync cmd "echo oops && exit 3" failed: exit status 3`, expectedLog: "oops"},
+		{name: "empty", cmd: ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if runtime.GOOS == "windows" {
+				t.Skip("uses sh")
+			}
+
+			f := newFixture(t)
+			defer f.teardown()
+
+			steps := []model.LiveUpdateStep{
+				model.LiveUpdateLocalPreSyncStep{Command: model.ToUnixCmdInDir(tc.cmd, f.Path())},
+				model.LiveUpdateSyncStep{Source: f.Path(), Dest: "/app"},
+			}
+			lu, err := model.NewLiveUpdate(steps, f.Path())
+			require.NoError(t, err)
+			m := manifestbuilder.New(f, "sancho").
+				WithK8sYAML(SanchoYAML).
+				WithImageTarget(imageTargetWithLiveUpdate(NewSanchoDockerBuildImageTarget(f), lu)).
+				Build()
+
+			// Only a.txt changed. gen.txt is the command's output.
+			// saved.txt was saved just before the command ran, and we
+			// haven't seen its change yet, so it's not the command's output.
+			start := time.Now()
+			f.lubad.clock = fakeClock{now: start}
+			saved := f.WriteFile("saved.txt", "saved")
+			require.NoError(t, os.Chtimes(saved, start, start.Add(-time.Millisecond)))
+
+			iTargetID := m.ImageTargetAt(0).ID()
+			state := store.BuildState{
+				LastResult:        alreadyBuilt,
+				RunningContainers: []store.ContainerInfo{TestContainerInfo},
+				FilesChangedSet:   map[string]bool{f.WriteFile("a.txt", "a"): true},
+			}
+			stateSet := store.BuildStateSet{iTargetID: state}
+
+			out := bytes.NewBuffer(nil)
+			ctx := logger.WithLogger(f.ctx, logger.NewTestLogger(out))
+			resultSet, err := f.lubad.BuildAndDeploy(ctx, f.st, m.TargetSpecs(), stateSet)
+			if tc.expectedErr != "" {
+				if assert.Error(t, err) {
+					assert.Contains(t, err.Error(), tc.expectedErr)
+					assert.True(t, IsDontFallBackError(err))
+				}
+				assert.Empty(t, f.cu.Calls, "should not sync when local_pre_sync fails")
+			} else {
+				require.NoError(t, err)
+				require.Len(t, f.cu.Calls, 1)
+			}
+
+			if tc.cmd == "" {
+				assert.NotContains(t, out.String(), "local_pre_sync")
+			} else {
+				assert.Contains(t, out.String(), fmt.Sprintf("Running local_pre_sync cmd %q", model.ToUnixCmd(tc.cmd).String()))
+				assert.Contains(t, out.String(), tc.expectedLog)
+			}
+
+			if tc.name == "success" {
+				// We sync what the command wrote, and don't live update again
+				// when its file changes come in.
+				testutils.AssertFilesInTar(t, tar.NewReader(f.cu.Calls[0].Archive), []testutils.ExpectedFile{
+					expectFile("app/a.txt", "a"),
+					expectFile("app/gen.txt", "compiled\n"),
+					expectMissing("app/saved.txt"),
+				})
+				result := resultSet[iTargetID].(store.LiveUpdateBuildResult)
+				assert.Equal(t, []string{f.JoinPath("gen.txt")}, result.LocalPreSyncFiles)
+			}
+			if tc.name == "empty" {
+				testutils.AssertFilesInTar(t, tar.NewReader(f.cu.Calls[0].Archive), []testutils.ExpectedFile{
+					expectFile("app/a.txt", "a"),
+					expectMissing("app/gen.txt"),
+				})
+			}
+		})
+	}
+}
+
+func TestLiveUpdateDeletedDirIsRemovedRecursively(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	m, stateSet := f.sharedAssetsManifest()
+	f.Rm("gen")
+
+	_, err := f.lubad.BuildAndDeploy(f.ctx, f.st, m.TargetSpecs(), stateSet)
+	require.NoError(t, err)
+	if assert.Len(t, f.cu.Calls, 1) {
+		assert.Equal(t, []string{"/app/assets"}, f.cu.Calls[0].ToDelete)
+	}
+}
+
+func TestLiveUpdateDoesNotDeleteDirSharedWithOtherSync(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	m, stateSet := f.sharedAssetsManifest()
+
+	out := bytes.NewBuffer(nil)
+	ctx := logger.WithLogger(f.ctx, logger.NewTestLogger(out))
+	_, err := f.lubad.BuildAndDeploy(ctx, f.st, m.TargetSpecs(), stateSet)
+	require.NoError(t, err)
+	if assert.Len(t, f.cu.Calls, 1) {
+		assert.Empty(t, f.cu.Calls[0].ToDelete)
+	}
+	assert.Contains(t, out.String(), "Won't delete 1 path(s) from container")
+	assert.Contains(t, out.String(), "- '/app/assets'")
+	assert.Contains(t, out.String(), "(via sync #1)")
+}
+
+func TestLiveUpdateSingleSyncIsNotNamed(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	f.WriteFile("src/a.txt", "a")
+	syncs := []model.LiveUpdateSyncStep{{Source: f.JoinPath("src"), Dest: "/app"}}
+	lu := assembleLiveUpdate(syncs, nil, false, nil, f)
+	m := manifestbuilder.New(f, "sancho").
+		WithK8sYAML(SanchoYAML).
+		WithImageTarget(imageTargetWithLiveUpdate(NewSanchoDockerBuildImageTarget(f), lu)).
+		Build()
+	stateSet := store.BuildStateSet{m.ImageTargetAt(0).ID(): store.BuildState{
+		LastResult:        alreadyBuilt,
+		RunningContainers: []store.ContainerInfo{TestContainerInfo},
+		FilesChangedSet:   map[string]bool{f.JoinPath("src", "a.txt"): true},
+	}}
+
+	out := bytes.NewBuffer(nil)
+	ctx := logger.WithLogger(f.ctx, logger.NewTestLogger(out))
+	_, err := f.lubad.BuildAndDeploy(ctx, f.st, m.TargetSpecs(), stateSet)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Will copy 1 file(s) to container")
+	assert.NotContains(t, out.String(), "via sync")
+}
+
+func TestLiveUpdateGzipMinBytes(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		minBytes int64
+		gzipped  bool
+	}{
+		{"never by default", 0, false},
+		{"below threshold", 5, false},
+		{"at threshold", 4, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.teardown()
+
+			m := NewSanchoLiveUpdateManifest(f)
+			f.WriteFile("a.txt", "aaaa")
+			f.st.WithState(func(state *store.EngineState) {
+				state.UpdateSettings = state.UpdateSettings.WithLiveUpdateCompressMinBytes(tc.minBytes)
+			})
+
+			state := store.BuildState{
+				LastResult:        alreadyBuilt,
+				RunningContainers: []store.ContainerInfo{TestContainerInfo},
+				FilesChangedSet:   map[string]bool{f.JoinPath("a.txt"): true},
+			}
+			stateSet := store.BuildStateSet{m.ImageTargetAt(0).ID(): state}
+
+			_, err := f.lubad.BuildAndDeploy(f.ctx, f.st, m.TargetSpecs(), stateSet)
+			require.NoError(t, err)
+			if assert.Len(t, f.cu.Calls, 1) {
+				assert.Equal(t, tc.gzipped, isGzipped(t, f.cu.Calls[0].Archive))
+			}
+		})
+	}
+}
+
+func isGzipped(t testing.TB, archive io.Reader) bool {
+	contents, err := io.ReadAll(archive)
+	require.NoError(t, err)
+	return bytes.HasPrefix(contents, []byte{0x1f, 0x8b})
+}
+
 type lcbadFixture struct {
 	*tempdir.TempDirFixture
 	t     testing.TB
 	ctx   context.Context
+	ma    *analytics.MemoryAnalytics
 	st    *store.TestingStore
 	cu    *containerupdate.FakeContainerUpdater
 	ps    *build.PipelineState
@@ -352,27 +1023,108 @@ type lcbadFixture struct {
 }
 
 func newFixture(t testing.TB) *lcbadFixture {
-	// HACK(maia): we don't need any real container updaters on this LiveUpdBaD since we're testing
-	// a func further down the flow that takes a ContainerUpdater as an arg, so just pass nils
-	lubad := NewLiveUpdateBuildAndDeployer(nil, nil, UpdateModeAuto, k8s.KubeContext("fake-context"), fakeClock{})
 	fakeContainerUpdater := &containerupdate.FakeContainerUpdater{}
-	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
+	lubad := NewLiveUpdateBuildAndDeployer(fakeContainerUpdaterSelector{cu: fakeContainerUpdater}, fakeClock{})
+	// Most tests care about the order of UpdateContainer calls, so update one container at a time.
+	lubad.maxParallelUpdates = 1
+	ctx, ma, _ := testutils.CtxAndAnalyticsForTest()
 	st := store.NewTestingStore()
 	return &lcbadFixture{
 		TempDirFixture: tempdir.NewTempDirFixture(t),
 		t:              t,
 		st:             st,
 		ctx:            ctx,
+		ma:             ma,
 		cu:             fakeContainerUpdater,
 		ps:             build.NewPipelineState(ctx, 1, lubad.clock),
 		lubad:          lubad,
 	}
 }
 
+// A clock that moves forward by step every time it's read.
+type steppingClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+// A ContainerUpdater where each update waits until n updates are in flight.
+type barrierContainerUpdater struct {
+	wg   sync.WaitGroup
+	errs map[container.ID]error
+}
+
+func newBarrierContainerUpdater(n int) *barrierContainerUpdater {
+	cu := &barrierContainerUpdater{errs: make(map[container.ID]error)}
+	cu.wg.Add(n)
+	return cu
+}
+
+func (cu *barrierContainerUpdater) UpdateContainer(ctx context.Context, cInfo store.ContainerInfo,
+	archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error {
+	cu.wg.Done()
+
+	done := make(chan struct{})
+	go func() {
+		cu.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		return fmt.Errorf("timed out waiting for parallel updates")
+	}
+	return cu.errs[cInfo.ContainerID]
+}
+
+func threeContainerInfos() []store.ContainerInfo {
+	var cInfos []store.ContainerInfo
+	for i := 0; i < 3; i++ {
+		cInfos = append(cInfos, store.ContainerInfo{
+			PodID:         k8s.PodID(fmt.Sprintf("mypod-%d", i)),
+			ContainerID:   container.ID(fmt.Sprintf("cid%d", i)),
+			ContainerName: "container",
+			Namespace:     "ns-foo",
+		})
+	}
+	return cInfos
+}
+
+// Cancels the context on the first update, as if Tilt were shutting down.
+type cancelingContainerUpdater struct {
+	cancel func()
+	err    error
+	calls  []container.ID
+}
+
+func (cu *cancelingContainerUpdater) UpdateContainer(ctx context.Context, cInfo store.ContainerInfo,
+	archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error {
+	cu.calls = append(cu.calls, cInfo.ContainerID)
+	cu.cancel()
+	return cu.err
+}
+
 func (f *lcbadFixture) teardown() {
 	f.TempDirFixture.TearDown()
 }
 
+func (f *lcbadFixture) pauseLiveUpdate(m model.Manifest) {
+	f.st.WithState(func(state *store.EngineState) {
+		mt := store.NewManifestTarget(m)
+		mt.State.LiveUpdatePaused = true
+		state.UpsertManifestTarget(mt)
+	})
+}
+
 func (f *lcbadFixture) newPathSet(paths ...string) model.PathSet {
 	return model.NewPathSet(paths, f.Path())
 }
@@ -391,3 +1143,94 @@ func expectMissing(path string) testutils.ExpectedFile {
 		Missing: true,
 	}
 }
+
+func TestFallBackOnGlob(t *testing.T) {
+	f := newFixture(t)
+	defer f.teardown()
+
+	syncs := []model.LiveUpdateSyncStep{{Source: f.Path(), Dest: "/src"}}
+	lu := assembleLiveUpdate(syncs, nil, false, []string{"**/*.go", "!vendor"}, f)
+	iTarget := imageTargetWithLiveUpdate(NewSanchoDockerBuildImageTarget(f), lu)
+
+	for _, tc := range []struct {
+		file           string
+		shouldFallBack bool
+	}{
+		{"cmd/main.go", true},
+		{"vendor/dep/dep.go", false},
+		{"README.md", false},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			path := f.WriteFile(tc.file, "hello")
+			_, err := liveUpdateInfoForStateTree(liveUpdateStateTree{
+				iTarget:      iTarget,
+				filesChanged: []string{path},
+				iTargetState: store.BuildState{
+					LastResult:        alreadyBuilt,
+					RunningContainers: []store.ContainerInfo{TestContainerInfo},
+				},
+			})
+			if tc.shouldFallBack {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "Detected change to fall_back_on file")
+				assert.True(t, ShouldFallBackForErr(err))
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+type fakeContainerUpdaterSelector struct {
+	cu containerupdate.ContainerUpdater
+}
+
+func (s fakeContainerUpdaterSelector) ContainerUpdaterForSpecs(ctx context.Context, specs []model.TargetSpec) containerupdate.ContainerUpdater {
+	return s.cu
+}
+
+// Two syncs that copy different files named x.txt into the same directory.
+// Both src/assets (deleted) and gen/assets sync to /app/assets.
+func (f *lcbadFixture) sharedAssetsManifest() (model.Manifest, store.BuildStateSet) {
+	f.WriteFile("gen/assets/bundle.js", "bundle")
+	syncs := []model.LiveUpdateSyncStep{
+		{Source: f.JoinPath("src", "assets"), Dest: "/app/assets"},
+		{Source: f.JoinPath("gen", "assets"), Dest: "/app/assets"},
+	}
+	lu := assembleLiveUpdate(syncs, nil, false, nil, f)
+	m := manifestbuilder.New(f, "sancho").
+		WithK8sYAML(SanchoYAML).
+		WithImageTarget(imageTargetWithLiveUpdate(NewSanchoDockerBuildImageTarget(f), lu)).
+		Build()
+
+	state := store.BuildState{
+		LastResult:        alreadyBuilt,
+		RunningContainers: []store.ContainerInfo{TestContainerInfo},
+		FilesChangedSet:   map[string]bool{f.JoinPath("src", "assets"): true},
+	}
+	return m, store.BuildStateSet{m.ImageTargetAt(0).ID(): state}
+}
+
+func (f *lcbadFixture) collidingSyncsManifest() (model.Manifest, store.BuildStateSet) {
+	f.WriteFile("foo/x.txt", "foo")
+	f.WriteFile("bar/x.txt", "bar")
+	syncs := []model.LiveUpdateSyncStep{
+		{Source: f.JoinPath("foo", "x.txt"), Dest: "/app/"},
+		{Source: f.JoinPath("bar", "x.txt"), Dest: "/app/"},
+	}
+	lu := assembleLiveUpdate(syncs, nil, false, nil, f)
+	m := manifestbuilder.New(f, "sancho").
+		WithK8sYAML(SanchoYAML).
+		WithImageTarget(imageTargetWithLiveUpdate(NewSanchoDockerBuildImageTarget(f), lu)).
+		Build()
+
+	state := store.BuildState{
+		LastResult:        alreadyBuilt,
+		RunningContainers: []store.ContainerInfo{TestContainerInfo},
+		FilesChangedSet: map[string]bool{
+			f.JoinPath("foo", "x.txt"): true,
+			f.JoinPath("bar", "x.txt"): true,
+		},
+	}
+	return m, store.BuildStateSet{m.ImageTargetAt(0).ID(): state}
+}
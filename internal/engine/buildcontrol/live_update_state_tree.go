@@ -16,7 +16,7 @@ type liveUpdateStateTree struct {
 }
 
 // Create a successful build result if the live update deploys successfully.
-func (t liveUpdateStateTree) createResultSet() store.BuildResultSet {
+func (t liveUpdateStateTree) createResultSet(info liveUpdInfo) store.BuildResultSet {
 	iTargetID := t.iTarget.ID()
 	state := t.iTargetState
 	res := state.LastResult
@@ -26,8 +26,12 @@ func (t liveUpdateStateTree) createResultSet() store.BuildResultSet {
 		liveUpdatedContainerIDs = append(liveUpdatedContainerIDs, c.ContainerID)
 	}
 
+	result := store.NewLiveUpdateBuildResult(res.TargetID(), liveUpdatedContainerIDs)
+	result.LocalPreSyncFiles = info.localPreSyncFiles
+	result.PausedFiles = info.pausedFiles
+
 	resultSet := store.BuildResultSet{}
-	resultSet[iTargetID] = store.NewLiveUpdateBuildResult(res.TargetID(), liveUpdatedContainerIDs)
+	resultSet[iTargetID] = result
 
 	// Invalidate all the image builds for images we depend on.
 	// Otherwise, the image builder will think the existing image ID
@@ -42,18 +46,19 @@ func (t liveUpdateStateTree) createResultSet() store.BuildResultSet {
 }
 
 func createResultSet(trees []liveUpdateStateTree, luInfos []liveUpdInfo) store.BuildResultSet {
-	liveUpdatedTargetIDs := make(map[model.TargetID]bool)
+	liveUpdatedTargets := make(map[model.TargetID]liveUpdInfo)
 	for _, info := range luInfos {
-		liveUpdatedTargetIDs[info.iTarget.ID()] = true
+		liveUpdatedTargets[info.iTarget.ID()] = info
 	}
 
 	resultSet := store.BuildResultSet{}
 	for _, t := range trees {
-		if !liveUpdatedTargetIDs[t.iTarget.ID()] {
+		info, ok := liveUpdatedTargets[t.iTarget.ID()]
+		if !ok {
 			// We didn't actually do a LiveUpdate for this tree
 			continue
 		}
-		resultSet = store.MergeBuildResultsSet(resultSet, t.createResultSet())
+		resultSet = store.MergeBuildResultsSet(resultSet, t.createResultSet(info))
 	}
 	return resultSet
 }
@@ -42,6 +42,8 @@ var BaseWireSet = wire.NewSet(
 	NewLocalTargetBuildAndDeployer,
 	containerupdate.NewDockerUpdater,
 	containerupdate.NewExecUpdater,
+	NewDefaultContainerUpdaterSelector,
+	wire.Bind(new(ContainerUpdaterSelector), new(*DefaultContainerUpdaterSelector)),
 	NewImageBuilder,
 
 	tracer.InitOpenTelemetry,
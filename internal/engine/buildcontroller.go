@@ -109,22 +109,29 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore, _ store
 
 		buildcontrol.LogBuildEntry(ctx, entry)
 
-		result, err := c.buildAndDeploy(ctx, st, entry)
-		st.Dispatch(buildcontrol.NewBuildCompleteAction(entry.name, entry.spanID, result, err))
+		result, fallBackReason, err := c.buildAndDeploy(ctx, st, entry)
+		action := buildcontrol.NewBuildCompleteAction(entry.name, entry.spanID, result, err)
+		action.FallBackReason = fallBackReason
+		st.Dispatch(action)
 	}()
 
 	return nil
 }
 
-func (c *BuildController) buildAndDeploy(ctx context.Context, st store.RStore, entry buildEntry) (store.BuildResultSet, error) {
+func (c *BuildController) buildAndDeploy(ctx context.Context, st store.RStore, entry buildEntry) (store.BuildResultSet, model.FallBackReason, error) {
 	targets := entry.targets
 	for _, target := range targets {
 		err := target.Validate()
 		if err != nil {
-			return store.BuildResultSet{}, err
+			return store.BuildResultSet{}, model.FallBackReasonNone, err
 		}
 	}
-	return c.b.BuildAndDeploy(ctx, st, targets, entry.buildStateSet)
+
+	if reporter, ok := c.b.(fallBackReporter); ok {
+		return reporter.buildAndDeployWithFallBackReason(ctx, st, targets, entry.buildStateSet)
+	}
+	result, err := c.b.BuildAndDeploy(ctx, st, targets, entry.buildStateSet)
+	return result, model.FallBackReasonNone, err
 }
 
 type BuildLogActionWriter struct {
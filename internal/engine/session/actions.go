@@ -7,6 +7,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/tilt-dev/tilt/internal/store"
+	tiltfileprint "github.com/tilt-dev/tilt/internal/tiltfile/print"
 	session "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
@@ -35,6 +36,13 @@ func HandleSessionUpdateStatusAction(state *store.EngineState, action SessionUpd
 		state.ExitSignal = true
 		if action.Status.Error != "" {
 			state.ExitError = errors.New(action.Status.Error)
+
+			// If the Tiltfile asked for a particular exit code with fail(code=N),
+			// keep its error, so that we can exit with that code.
+			tfErr := state.TiltfileState.LastBuild().Error
+			if _, ok := tiltfileprint.ExitCode(tfErr); ok && tfErr.Error() == action.Status.Error {
+				state.ExitError = tfErr
+			}
 		}
 	}
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/testutils/manifestbuilder"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	tiltfileprint "github.com/tilt-dev/tilt/internal/tiltfile/print"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -44,6 +45,27 @@ func TestExitControlCI_TiltfileFailure(t *testing.T) {
 	f.store.requireExitSignalWithError("fake Tiltfile error")
 }
 
+func TestExitControlCI_TiltfileFailureKeepsExitCode(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+	defer f.TearDown()
+
+	f.store.WithState(func(state *store.EngineState) {
+		state.TiltfileState = &store.ManifestState{}
+		state.TiltfileState.AddCompletedBuild(model.BuildRecord{
+			Error: tiltfileprint.NewExitCodeError("fake Tiltfile error", 3),
+		})
+	})
+
+	_ = f.c.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	f.store.requireExitSignalWithError("fake Tiltfile error")
+
+	state := f.store.RLockState()
+	defer f.store.RUnlockState()
+	code, ok := tiltfileprint.ExitCode(state.ExitError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, code)
+}
+
 func TestExitControlIdempotent(t *testing.T) {
 	f := newFixture(t, store.EngineModeCI)
 	defer f.TearDown()
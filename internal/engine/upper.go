@@ -171,6 +171,8 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 		handleSwitchTerminalModeAction(state, action)
 	case server.OverrideTriggerModeAction:
 		handleOverrideTriggerModeAction(ctx, state, action)
+	case server.SetLiveUpdatePausedAction:
+		handleSetLiveUpdatePausedAction(ctx, state, action)
 	case local.CmdCreateAction:
 		local.HandleCmdCreateAction(state, action)
 	case local.CmdUpdateStatusAction:
@@ -259,6 +261,26 @@ func handleBuildResults(engineState *store.EngineState,
 		status.ClearPendingChangesBefore(br.StartTime)
 	}
 
+	// The files that local_pre_sync wrote were synced along with the
+	// change that kicked off the build, so they don't need another one.
+	for id, result := range results {
+		if result, ok := result.(store.LiveUpdateBuildResult); ok {
+			ms.MutableBuildStatus(id).ClearPendingFileChangesBefore(result.LocalPreSyncFiles, br.FinishTime)
+		}
+	}
+
+	// Remember the files we skipped while live updates were paused, so that we
+	// can sync them on resume. A full build picks them all up, so forget them.
+	for id, result := range results {
+		switch result := result.(type) {
+		case store.LiveUpdateBuildResult:
+			ms.MutableBuildStatus(id).AddPausedFileChanges(result.PausedFiles)
+		case nil:
+		default:
+			ms.MutableBuildStatus(id).PausedFileChanges = nil
+		}
+	}
+
 	if isBuildSuccess {
 		ms.LastSuccessfulDeployTime = br.FinishTime
 	}
@@ -359,6 +381,7 @@ func handleBuildCompleted(ctx context.Context, engineState *store.EngineState, c
 	bs.Error = err
 	bs.FinishTime = cb.FinishTime
 	bs.BuildTypes = cb.Result.BuildTypes()
+	bs.FallBackReason = cb.FallBackReason
 	if bs.SpanID != "" {
 		bs.WarningCount = len(engineState.LogStore.Warnings(bs.SpanID))
 	}
@@ -814,3 +837,37 @@ func handleOverrideTriggerModeAction(ctx context.Context, state *store.EngineSta
 		mt.Manifest.TriggerMode = action.TriggerMode
 	}
 }
+
+func handleSetLiveUpdatePausedAction(ctx context.Context, state *store.EngineState,
+	action server.SetLiveUpdatePausedAction) {
+	for _, mName := range action.ManifestNames {
+		ms, ok := state.ManifestState(mName)
+		if !ok {
+			// We validate manifest names when we receive a request, so this should never happen
+			logger.Get(ctx).Errorf("INTERNAL ERROR pausing live update: no such manifest %q", mName)
+			continue
+		}
+		if ms.LiveUpdatePaused == action.Paused {
+			continue
+		}
+
+		ms.LiveUpdatePaused = action.Paused
+		if action.Paused {
+			logger.Get(ctx).Infof("Paused live updates for %s. Changed files will be synced when you resume.", mName)
+			continue
+		}
+
+		// Queue up the files we skipped while paused. They're de-duplicated,
+		// so each gets synced once, as it is now.
+		count := 0
+		now := time.Now()
+		for id, status := range ms.BuildStatuses {
+			for file := range status.PausedFileChanges {
+				ms.AddPendingFileChange(id, file, now)
+				count++
+			}
+			status.PausedFileChanges = nil
+		}
+		logger.Get(ctx).Infof("Resumed live updates for %s. Syncing %d file(s) that changed while paused.", mName, count)
+	}
+}
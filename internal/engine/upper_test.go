@@ -3824,6 +3824,159 @@ func TestOverrideTriggerModeBadTriggerModeLogsError(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestSetLiveUpdatePausedEvent(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	manifest := f.newManifest("foo")
+	f.Start([]model.Manifest{manifest})
+
+	f.upper.store.Dispatch(server.SetLiveUpdatePausedAction{
+		ManifestNames: []model.ManifestName{"foo"},
+		Paused:        true,
+	})
+
+	f.WaitUntilManifestState("live update paused", "foo", func(ms store.ManifestState) bool {
+		return ms.LiveUpdatePaused
+	})
+
+	err := f.log.WaitUntilContains("Paused live updates for foo", stdTimeout)
+	require.NoError(t, err)
+
+	err = f.Stop()
+	require.NoError(t, err)
+}
+
+func TestFileChangesWhileLiveUpdatePausedStillQueueBuilds(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	manifest := f.newManifest("foo")
+	iTargetID := manifest.ImageTargetAt(0).ID()
+	state := store.NewState()
+	state.UpsertManifestTarget(store.NewManifestTarget(manifest))
+
+	handleSetLiveUpdatePausedAction(f.ctx, state, server.SetLiveUpdatePausedAction{
+		ManifestNames: []model.ManifestName{"foo"},
+		Paused:        true,
+	})
+
+	path := f.JoinPath("Dockerfile")
+	fw := &v1alpha1.FileWatch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo",
+			Annotations: map[string]string{v1alpha1.AnnotationTargetID: iTargetID.String()},
+		},
+		Status: v1alpha1.FileWatchStatus{
+			FileEvents: []v1alpha1.FileEvent{{Time: metav1.NowMicro(), SeenFiles: []string{path}}},
+		},
+	}
+	filewatch.HandleFileWatchUpdateStatusEvent(f.ctx, state, filewatch.NewFileWatchUpdateStatusAction(fw))
+
+	// Pausing only skips the sync, so changes that need a rebuild still get one.
+	assert.Contains(t, state.BuildStatus(iTargetID).PendingFileChanges, path)
+}
+
+func TestResumingLiveUpdateSyncsFilesChangedWhilePaused(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	manifest := f.newManifest("foo")
+	iTargetID := manifest.ImageTargetAt(0).ID()
+	state := store.NewState()
+	mt := store.NewManifestTarget(manifest)
+	state.UpsertManifestTarget(mt)
+
+	pause := func(paused bool) {
+		handleSetLiveUpdatePausedAction(f.ctx, state, server.SetLiveUpdatePausedAction{
+			ManifestNames: []model.ManifestName{"foo"},
+			Paused:        paused,
+		})
+	}
+	pause(true)
+
+	// Two builds skip the same file while paused.
+	a, b := f.JoinPath("a.txt"), f.JoinPath("b.txt")
+	start := time.Now()
+	for _, files := range [][]string{{a}, {a, b}} {
+		result := store.NewLiveUpdateBuildResult(iTargetID, nil)
+		result.PausedFiles = files
+		br := model.BuildRecord{StartTime: start, FinishTime: start}
+		handleBuildResults(state, mt, br, store.BuildResultSet{iTargetID: result})
+	}
+	status := mt.State.BuildStatuses[iTargetID]
+	assert.Empty(t, status.PendingFileChanges)
+
+	// On resume, each file is queued once.
+	pause(false)
+	assert.Len(t, status.PendingFileChanges, 2)
+	assert.Contains(t, status.PendingFileChanges, a)
+	assert.Contains(t, status.PendingFileChanges, b)
+	assert.Empty(t, status.PausedFileChanges)
+}
+
+func TestFullBuildForgetsFilesChangedWhilePaused(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	manifest := f.newManifest("foo")
+	iTargetID := manifest.ImageTargetAt(0).ID()
+	state := store.NewState()
+	mt := store.NewManifestTarget(manifest)
+	state.UpsertManifestTarget(mt)
+
+	status := mt.State.MutableBuildStatus(iTargetID)
+	status.AddPausedFileChanges([]string{f.JoinPath("a.txt")})
+
+	ref := container.MustParseNamedTagged("gcr.io/some-project-162817/foo:tilt-123")
+	result := store.NewImageBuildResultSingleRef(iTargetID, ref)
+	handleBuildResults(state, mt, model.BuildRecord{}, store.BuildResultSet{iTargetID: result})
+	assert.Empty(t, status.PausedFileChanges)
+}
+
+func TestSetLiveUpdatePausedSkipsUnknownManifests(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	state := store.NewState()
+	state.UpsertManifestTarget(store.NewManifestTarget(f.newManifest("foo")))
+
+	handleSetLiveUpdatePausedAction(f.ctx, state, server.SetLiveUpdatePausedAction{
+		ManifestNames: []model.ManifestName{"missing", "foo"},
+		Paused:        true,
+	})
+
+	ms, _ := state.ManifestState("foo")
+	assert.True(t, ms.LiveUpdatePaused)
+}
+
+func TestLocalPreSyncFilesDontTriggerAnotherBuild(t *testing.T) {
+	f := newTestFixture(t)
+	defer f.TearDown()
+
+	manifest := f.newManifest("foo")
+	iTargetID := manifest.ImageTargetAt(0).ID()
+	state := store.NewState()
+	mt := store.NewManifestTarget(manifest)
+	state.UpsertManifestTarget(mt)
+
+	start := time.Now()
+	gen := f.JoinPath("gen.txt")
+	edited := f.JoinPath("edited.txt")
+	status := mt.State.MutableBuildStatus(iTargetID)
+	status.PendingFileChanges[gen] = start.Add(time.Second)
+	status.PendingFileChanges[edited] = start.Add(time.Second)
+
+	result := store.NewLiveUpdateBuildResult(iTargetID, nil)
+	result.LocalPreSyncFiles = []string{gen}
+	br := model.BuildRecord{StartTime: start, FinishTime: start.Add(2 * time.Second)}
+	handleBuildResults(state, mt, br, store.BuildResultSet{iTargetID: result})
+
+	// The user edited a file mid-build, so it still needs a build.
+	assert.NotContains(t, status.PendingFileChanges, gen)
+	assert.Contains(t, status.PendingFileChanges, edited)
+}
+
 type testFixture struct {
 	*tempdir.TempDirFixture
 	t                          *testing.T
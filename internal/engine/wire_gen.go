@@ -42,7 +42,8 @@ func provideFakeBuildAndDeployer(ctx context.Context, docker2 docker.Client, kCl
 	if err != nil {
 		return nil, err
 	}
-	liveUpdateBuildAndDeployer := buildcontrol.NewLiveUpdateBuildAndDeployer(dockerUpdater, execUpdater, buildcontrolUpdateMode, kubeContext, clock)
+	defaultContainerUpdaterSelector := buildcontrol.NewDefaultContainerUpdaterSelector(dockerUpdater, execUpdater, buildcontrolUpdateMode, kubeContext)
+	liveUpdateBuildAndDeployer := buildcontrol.NewLiveUpdateBuildAndDeployer(defaultContainerUpdaterSelector, clock)
 	labels := _wireLabelsValue
 	dockerImageBuilder := build.NewDockerImageBuilder(docker2, labels)
 	dockerBuilder := build.DefaultDockerBuilder(dockerImageBuilder)
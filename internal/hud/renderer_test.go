@@ -729,6 +729,21 @@ func TestTiltfileResourcePending(t *testing.T) {
 	rtf.run("Tiltfile resource pending", 80, 20, v, vs)
 }
 
+func TestRenderLiveUpdatePaused(t *testing.T) {
+	rtf := newRendererTestFixture(t)
+	vs := fakeViewState(1, view.CollapseNo)
+	v := newView(view.Resource{
+		Name: "vigoda",
+		BuildHistory: []model.BuildRecord{{
+			StartTime:  time.Now().Add(-time.Second),
+			FinishTime: time.Now(),
+		}},
+		ResourceInfo:     view.K8sResourceInfo{PodStatus: "Running", RunStatus: v1alpha1.RuntimeStatusOK},
+		LiveUpdatePaused: true,
+	})
+	rtf.run("live update paused", 80, 20, v, vs)
+}
+
 func TestRenderEscapedNbsp(t *testing.T) {
 	rtf := newRendererTestFixture(t)
 	plainVs := fakeViewState(1, view.CollapseNo)
@@ -155,6 +155,9 @@ func (v *ResourceView) titleTextName() rty.Component {
 	if len(v.warnings()) > 0 {
 		name = fmt.Sprintf("%s %s", v.res.Name, "— Warning ⚠️")
 	}
+	if v.res.LiveUpdatePaused {
+		name = fmt.Sprintf("%s %s", name, "— Live Update Paused")
+	}
 	sb.Fg(tcell.ColorDefault).Text(name)
 	return sb.Build()
 }
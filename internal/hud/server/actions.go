@@ -24,3 +24,10 @@ type OverrideTriggerModeAction struct {
 }
 
 func (OverrideTriggerModeAction) Action() {}
+
+type SetLiveUpdatePausedAction struct {
+	ManifestNames []model.ManifestName
+	Paused        bool
+}
+
+func (SetLiveUpdatePausedAction) Action() {}
@@ -49,6 +49,11 @@ type overrideTriggerModePayload struct {
 	TriggerMode   int      `json:"trigger_mode"`
 }
 
+type setLiveUpdatePausedPayload struct {
+	ManifestNames []string `json:"manifest_names"`
+	Paused        bool     `json:"paused"`
+}
+
 type HeadsUpServer struct {
 	ctx        context.Context
 	store      *store.Store
@@ -84,6 +89,7 @@ func ProvideHeadsUpServer(
 	r.HandleFunc("/api/analytics_opt", s.HandleAnalyticsOpt)
 	r.HandleFunc("/api/trigger", s.HandleTrigger)
 	r.HandleFunc("/api/override/trigger_mode", s.HandleOverrideTriggerMode)
+	r.HandleFunc("/api/override/live_update_paused", s.HandleSetLiveUpdatePaused)
 	r.HandleFunc("/api/snapshot/new", s.HandleNewSnapshot).Methods("POST")
 	// this endpoint is only used for testing snapshots in development
 	r.HandleFunc("/api/snapshot/{snapshot_id}", s.SnapshotJSON)
@@ -297,6 +303,34 @@ func (s *HeadsUpServer) HandleOverrideTriggerMode(w http.ResponseWriter, req *ht
 	})
 }
 
+func (s *HeadsUpServer) HandleSetLiveUpdatePaused(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "must be POST request", http.StatusBadRequest)
+		return
+	}
+
+	var payload setLiveUpdatePausedPayload
+
+	decoder := json.NewDecoder(req.Body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error parsing JSON payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	err = checkManifestsExist(s.store, payload.ManifestNames)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.store.Dispatch(SetLiveUpdatePausedAction{
+		ManifestNames: model.ManifestNames(payload.ManifestNames),
+		Paused:        payload.Paused,
+	})
+}
+
 /* -- SNAPSHOT: SENDING SNAPSHOT TO SERVER -- */
 type snapshotURLJson struct {
 	Url string `json:"url"`
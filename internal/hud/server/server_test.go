@@ -324,6 +324,38 @@ func TestHandleOverrideTriggerModeDispatchesEvent(t *testing.T) {
 	assert.Equal(t, expected, action)
 }
 
+func TestHandleSetLiveUpdatePausedReturnsErrorForBadManifest(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("foo", "bar")
+
+	payload := `{"manifest_names":["foo", "baz"], "paused": true}`
+	status, respBody := f.makeReq("/api/override/live_update_paused", f.serv.HandleSetLiveUpdatePaused, http.MethodPost, payload)
+
+	require.Equal(t, http.StatusBadRequest, status, "handler returned wrong status code")
+	require.Contains(t, respBody, "no manifest found with name 'baz'")
+	store.AssertNoActionOfType(t, reflect.TypeOf(server.SetLiveUpdatePausedAction{}), f.getActions)
+}
+
+func TestHandleSetLiveUpdatePausedDispatchesEvent(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("foo", "bar")
+
+	payload := `{"manifest_names":["foo"], "paused": true}`
+	status, _ := f.makeReq("/api/override/live_update_paused", f.serv.HandleSetLiveUpdatePaused, http.MethodPost, payload)
+
+	require.Equal(t, http.StatusOK, status, "handler returned wrong status code")
+
+	a := store.WaitForAction(t, reflect.TypeOf(server.SetLiveUpdatePausedAction{}), f.getActions)
+	action, ok := a.(server.SetLiveUpdatePausedAction)
+	if !ok {
+		t.Fatalf("Action was not of type 'SetLiveUpdatePausedAction': %+v", action)
+	}
+
+	expected := server.SetLiveUpdatePausedAction{
+		ManifestNames: []model.ManifestName{"foo"},
+		Paused:        true,
+	}
+	assert.Equal(t, expected, action)
+}
+
 func TestHandleNewSnapshot(t *testing.T) {
 	f := newTestFixture(t)
 
@@ -128,6 +128,8 @@ type Resource struct {
 	ResourceInfo ResourceInfoView
 
 	IsTiltfile bool
+
+	LiveUpdatePaused bool
 }
 
 func (r Resource) DockerComposeTarget() DCResourceInfo {
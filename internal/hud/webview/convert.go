@@ -243,6 +243,7 @@ func toUIResource(mt *store.ManifestTarget, s store.EngineState) (*v1alpha1.UIRe
 			TriggerMode:       int32(mt.Manifest.TriggerMode),
 			HasPendingChanges: hasPendingChanges,
 			Queued:            s.ManifestInTriggerQueue(name),
+			LiveUpdatePaused:  ms.LiveUpdatePaused,
 		},
 	}
 
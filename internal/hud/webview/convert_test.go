@@ -224,6 +224,17 @@ func TestTriggerMode(t *testing.T) {
 	assert.Equal(t, model.TriggerModeManualWithAutoInit, model.TriggerMode(newM.TriggerMode))
 }
 
+func TestLiveUpdatePaused(t *testing.T) {
+	state := newState(nil)
+	targ := store.NewManifestTarget(fooManifest)
+	targ.State = &store.ManifestState{LiveUpdatePaused: true}
+	state.UpsertManifestTarget(targ)
+
+	v := completeProtoView(t, *state)
+	newM, _ := findResource(model.ManifestName("foo"), v)
+	assert.True(t, newM.LiveUpdatePaused)
+}
+
 func TestFeatureFlags(t *testing.T) {
 	state := newState(nil)
 	state.Features = map[string]bool{"foo_feature": true}
@@ -303,6 +314,8 @@ func TestBuildHistory(t *testing.T) {
 		FinishTime: time.Now().Add(-19 * time.Minute),
 		Reason:     model.BuildReasonFlagCrash,
 		BuildTypes: []model.BuildType{model.BuildTypeImage, model.BuildTypeK8s},
+
+		FallBackReason: model.FallBackReasonNoMatch,
 	}
 	buildRecords := []model.BuildRecord{br1, br2, br3}
 
@@ -323,6 +336,7 @@ func TestBuildHistory(t *testing.T) {
 		timecmp.AssertTimeEqual(t, expected.StartTime, actual.StartTime)
 		timecmp.AssertTimeEqual(t, expected.FinishTime, actual.FinishTime)
 		require.Equal(t, i == 2, actual.IsCrashRebuild)
+		require.Equal(t, string(expected.FallBackReason), actual.FallBackReason)
 	}
 }
 
@@ -81,6 +81,7 @@ func ToBuildTerminated(br model.BuildRecord, logStore *logstore.LogStore) v1alph
 		FinishTime:     metav1.NewMicroTime(br.FinishTime),
 		IsCrashRebuild: br.Reason.IsCrashOnly(),
 		SpanID:         string(br.SpanID),
+		FallBackReason: string(br.FallBackReason),
 	}
 }
 
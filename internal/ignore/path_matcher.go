@@ -3,6 +3,8 @@ package ignore
 import (
 	"fmt"
 	"path/filepath"
+	"regexp"
+	"regexp/syntax"
 
 	"github.com/pkg/errors"
 
@@ -61,7 +63,15 @@ func IgnoresToMatcher(ignores []v1alpha1.IgnoreDef) (model.PathMatcher, error) {
 				return nil, fmt.Errorf("invalid ignore def: %v", err)
 			}
 			ignoreMatchers = append(ignoreMatchers, m)
-		} else {
+		}
+		if len(ignoreDef.Regexes) != 0 {
+			m, err := NewRegexMatcher(ignoreDef.BasePath, ignoreDef.Regexes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ignore def: %v", err)
+			}
+			ignoreMatchers = append(ignoreMatchers, m)
+		}
+		if len(ignoreDef.Patterns) == 0 && len(ignoreDef.Regexes) == 0 {
 			m, err := NewDirectoryMatcher(ignoreDef.BasePath)
 			if err != nil {
 				return nil, fmt.Errorf("invalid ignore def: %v", err)
@@ -130,3 +140,94 @@ func (d DirectoryMatcher) Matches(p string) (bool, error) {
 func (d DirectoryMatcher) MatchesEntireDir(p string) (bool, error) {
 	return d.Matches(p)
 }
+
+// RegexMatcher matches paths under a directory whose path relative to that
+// directory (with forward slashes) matches any of a list of regular expressions.
+type RegexMatcher struct {
+	dir     string
+	regexes []*regexp.Regexp
+
+	// For each regex, whether a match on a directory is also a match on
+	// everything inside it (see matchesAllChildren).
+	matchesChildren []bool
+}
+
+var _ model.PathMatcher = RegexMatcher{}
+
+func NewRegexMatcher(dir string, regexes []string) (RegexMatcher, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return RegexMatcher{}, errors.Wrapf(err, "failed to get abs path of '%s'", dir)
+	}
+
+	m := RegexMatcher{dir: dir}
+	for _, r := range regexes {
+		re, err := regexp.Compile(r)
+		if err != nil {
+			return RegexMatcher{}, fmt.Errorf("invalid regex %q: %v", r, err)
+		}
+		m.regexes = append(m.regexes, re)
+		m.matchesChildren = append(m.matchesChildren, matchesAllChildren(re))
+	}
+	return m, nil
+}
+
+func (m RegexMatcher) relPath(p string) (string, bool) {
+	rel, isChild := ospath.Child(m.dir, p)
+	if !isChild || rel == "." {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+func (m RegexMatcher) Matches(p string) (bool, error) {
+	rel, ok := m.relPath(p)
+	if !ok {
+		return false, nil
+	}
+	for _, re := range m.regexes {
+		if re.MatchString(rel) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m RegexMatcher) MatchesEntireDir(p string) (bool, error) {
+	rel, ok := m.relPath(p)
+	if !ok {
+		return false, nil
+	}
+	for i, re := range m.regexes {
+		if m.matchesChildren[i] && re.MatchString(rel) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// If a regex matches "dir", does it match "dir/any/child"?
+//
+// A match is a substring, so it's still there when we append to the path,
+// unless the regex asserts something about the end of the text or the
+// character after the match (like "\.log$" or "tmp\b").
+func matchesAllChildren(re *regexp.Regexp) bool {
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return false
+	}
+	return !hasEndAssertion(parsed)
+}
+
+func hasEndAssertion(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpEndText, syntax.OpEndLine, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasEndAssertion(sub) {
+			return true
+		}
+	}
+	return false
+}
@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -148,3 +150,68 @@ func TestIgnores(t *testing.T) {
 		})
 	}
 }
+
+func TestRegexMatcher(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	m, err := NewRegexMatcher(f.Path(), []string{`^build-\d+`, `\.tmp$`})
+	require.NoError(t, err)
+
+	cases := []struct {
+		path             string
+		matches          bool
+		matchesEntireDir bool
+	}{
+		{"build-123", true, true},
+		{"build-123/out.js", true, true},
+		{"build-abc", false, false},
+		{"src/build-123", false, false},
+		{"foo.tmp", true, false},
+		{"src/foo.tmp", true, false},
+		{"foo.tmp/bar", false, false},
+		{".", false, false},
+		{"../build-123", false, false},
+	}
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			p := filepath.Join(f.Path(), c.path)
+			matches, err := m.Matches(p)
+			require.NoError(t, err)
+			assert.Equal(t, c.matches, matches)
+
+			matchesEntireDir, err := m.MatchesEntireDir(p)
+			require.NoError(t, err)
+			assert.Equal(t, c.matchesEntireDir, matchesEntireDir)
+		})
+	}
+}
+
+func TestIgnoresToMatcherRegexes(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	m, err := IgnoresToMatcher([]v1alpha1.IgnoreDef{
+		{BasePath: f.Path(), Patterns: []string{"*.log"}, Regexes: []string{`^gen_[a-z]+_v\d\.go$`}},
+	})
+	require.NoError(t, err)
+
+	for path, expected := range map[string]bool{
+		"gen_api_v2.go":  true,
+		"gen_api_v2.txt": false,
+		"debug.log":      true,
+		"main.go":        false,
+	} {
+		matches, err := m.Matches(f.JoinPath(path))
+		require.NoError(t, err)
+		assert.Equal(t, expected, matches, path)
+	}
+}
+
+func TestIgnoresToMatcherInvalidRegex(t *testing.T) {
+	_, err := IgnoresToMatcher([]v1alpha1.IgnoreDef{
+		{BasePath: "/src", Regexes: []string{`gen_(`}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid regex "gen_("`)
+}
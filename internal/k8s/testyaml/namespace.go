@@ -0,0 +1,58 @@
+package testyaml
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	yamlDecoder "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// Sets metadata.namespace on every object in the YAML, including
+// the items of a List. Panics if the YAML can't be parsed.
+func WithNamespace(yamlStr string, ns string) string {
+	decoder := yamlDecoder.NewYAMLOrJSONDecoder(strings.NewReader(yamlStr), 4096)
+	var docs []string
+	for {
+		obj := map[string]interface{}{}
+		err := decoder.Decode(&obj)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			panic(fmt.Sprintf("WithNamespace: decoding yaml: %v", err))
+		}
+		if len(obj) == 0 {
+			continue
+		}
+
+		if obj["kind"] == "List" {
+			items, _ := obj["items"].([]interface{})
+			for _, item := range items {
+				itemObj, ok := item.(map[string]interface{})
+				if !ok {
+					panic(fmt.Sprintf("WithNamespace: unexpected List item: %v", item))
+				}
+				setNamespace(itemObj, ns)
+			}
+		} else {
+			setNamespace(obj, ns)
+		}
+
+		doc, err := yaml.Marshal(obj)
+		if err != nil {
+			panic(fmt.Sprintf("WithNamespace: encoding yaml: %v", err))
+		}
+		docs = append(docs, string(doc))
+	}
+	return strings.Join(docs, "---\n")
+}
+
+func setNamespace(obj map[string]interface{}, ns string) {
+	err := unstructured.SetNestedField(obj, ns, "metadata", "namespace")
+	if err != nil {
+		panic(fmt.Sprintf("WithNamespace: %v", err))
+	}
+}
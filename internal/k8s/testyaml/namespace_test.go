@@ -0,0 +1,38 @@
+package testyaml_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+)
+
+func TestWithNamespace(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(testyaml.WithNamespace(testyaml.SanchoYAML, "test-ns"))
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+	assert.Equal(t, k8s.Namespace("test-ns"), entities[0].Namespace())
+	assert.Equal(t, "sancho", entities[0].Name())
+}
+
+func TestWithNamespaceMultipleDocs(t *testing.T) {
+	yaml := testyaml.WithNamespace(testyaml.BlorgBackendYAML, "test-ns")
+	entities, err := k8s.ParseYAMLFromString(yaml)
+	require.NoError(t, err)
+	require.Len(t, entities, 2)
+	for _, e := range entities {
+		assert.Equal(t, k8s.Namespace("test-ns"), e.Namespace())
+	}
+}
+
+func TestWithNamespaceList(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(testyaml.WithNamespace(testyaml.DoggosListYAML, "test-ns"))
+	require.NoError(t, err)
+	require.Len(t, entities, 2)
+	for _, e := range entities {
+		assert.Equal(t, k8s.Namespace("test-ns"), e.Namespace())
+	}
+}
@@ -1,6 +1,7 @@
 package testyaml
 
 import (
+	"strconv"
 	"strings"
 )
 
@@ -1572,6 +1573,10 @@ spec:
 `
 
 func Deployment(name string, imageName string) string {
+	return DeploymentWithReplicas(name, imageName, 1)
+}
+
+func DeploymentWithReplicas(name string, imageName string, replicas int) string {
 	result := `
 apiVersion: apps/v1
 kind: Deployment
@@ -1580,7 +1585,7 @@ metadata:
   labels:
     app: NAME
 spec:
-  replicas: 1
+  replicas: REPLICAS
   selector:
     matchLabels:
       app: NAME
@@ -1595,6 +1600,7 @@ spec:
 `
 	result = strings.Replace(result, "NAME", name, -1)
 	result = strings.Replace(result, "IMAGE", imageName, -1)
+	result = strings.Replace(result, "REPLICAS", strconv.Itoa(replicas), -1)
 	return result
 }
 
@@ -0,0 +1,26 @@
+package testyaml_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+)
+
+func TestDeploymentWithReplicas(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(testyaml.DeploymentWithReplicas("dep", "gcr.io/dep", 3))
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+
+	dep, ok := entities[0].Obj.(*appsv1.Deployment)
+	require.True(t, ok, "expected apps/v1 Deployment, got %T", entities[0].Obj)
+	require.NotNil(t, dep.Spec.Replicas)
+	assert.Equal(t, int32(3), *dep.Spec.Replicas)
+	assert.Equal(t, map[string]string{"app": "dep"}, dep.Spec.Selector.MatchLabels)
+	assert.Equal(t, map[string]string{"app": "dep"}, dep.Spec.Template.Labels)
+	assert.Equal(t, "gcr.io/dep", dep.Spec.Template.Spec.Containers[0].Image)
+}
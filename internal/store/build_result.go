@@ -12,6 +12,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -91,6 +92,13 @@ type LiveUpdateBuildResult struct {
 	// The contents of the container have diverged from the image it's built on,
 	// so we need to keep track of that.
 	LiveUpdatedContainerIDs []container.ID
+
+	// The files that the local_pre_sync cmds wrote and that we synced.
+	// Their file changes don't need another live update.
+	LocalPreSyncFiles []string
+
+	// The changed files we didn't sync because live updates were paused.
+	PausedFiles []string
 }
 
 func (r LiveUpdateBuildResult) TargetID() model.TargetID   { return r.id }
@@ -488,6 +496,7 @@ func RunningContainersForTargetForOnePod(iTarget model.ImageTarget, runtimeState
 		return nil, nil
 	}
 
+	containerName := iTarget.LiveUpdateInfo().ContainerName
 	var containers []ContainerInfo
 	for _, c := range pod.Containers {
 		// Only return containers matching our image
@@ -495,6 +504,9 @@ func RunningContainersForTargetForOnePod(iTarget model.ImageTarget, runtimeState
 		if err != nil || imageRef == nil || iTarget.Refs.ClusterRef().Name() != imageRef.Name() {
 			continue
 		}
+		if containerName != "" && c.Name != containerName {
+			continue
+		}
 		if c.ID == "" || c.Name == "" || c.State.Running == nil {
 			// If we're missing any relevant info for this container, OR if the
 			// container isn't running, we can't update it in place.
@@ -511,6 +523,42 @@ func RunningContainersForTargetForOnePod(iTarget model.ImageTarget, runtimeState
 		})
 	}
 
+	if containerName != "" && len(containers) == 0 {
+		// Ephemeral containers (e.g., added with `kubectl debug`) usually run
+		// a tooling image rather than the one we built, so we only pick them by name.
+		for _, c := range pod.EphemeralContainers {
+			if c.Name != containerName {
+				continue
+			}
+			if c.ID == "" || c.State.Running == nil {
+				return nil, nil
+			}
+			containers = append(containers, ContainerInfo{
+				PodID:         k8s.PodID(pod.Name),
+				ContainerID:   container.ID(c.ID),
+				ContainerName: container.Name(c.Name),
+				Namespace:     k8s.Namespace(pod.Namespace),
+			})
+		}
+	}
+
+	if containerName != "" && len(containers) == 0 {
+		// Tell the user why we can't live update, rather than quietly
+		// rebuilding every time because of a typo.
+		var names []string
+		found := false
+		for _, cs := range [][]v1alpha1.Container{pod.Containers, pod.EphemeralContainers} {
+			for _, c := range cs {
+				names = append(names, c.Name)
+				found = found || c.Name == containerName
+			}
+		}
+		if len(names) > 0 && !found {
+			return nil, fmt.Errorf("live_update_container %q not found in pod %s (containers: %s)",
+				containerName, pod.Name, sliceutils.QuotedStringList(names))
+		}
+	}
+
 	return containers, nil
 }
 
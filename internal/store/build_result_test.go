@@ -4,8 +4,10 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -30,3 +32,82 @@ func TestOneAndOnlyLiveUpdatedContainerID(t *testing.T) {
 	}
 	assert.Equal(t, "cA", string(set.OneAndOnlyLiveUpdatedContainerID()))
 }
+
+func TestRunningContainersForTargetForOnePodContainerName(t *testing.T) {
+	ref := container.MustParseSelector("gcr.io/some-project-162817/sancho")
+	running := v1alpha1.ContainerState{Running: &v1alpha1.ContainerStateRunning{}}
+	pod := v1alpha1.Pod{
+		Name:      "sancho-pod",
+		Namespace: "default",
+		Containers: []v1alpha1.Container{
+			{Name: "sancho", ID: "c-sancho", Image: "gcr.io/some-project-162817/sancho:tilt-123", State: running},
+			{Name: "sidecar", ID: "c-sidecar", Image: "gcr.io/some-project-162817/sancho:tilt-123", State: running},
+		},
+	}
+	state := NewK8sRuntimeStateWithPods(model.Manifest{}, pod)
+
+	iTarget := model.MustNewImageTarget(ref).WithBuildDetails(model.DockerBuild{})
+	cInfos, err := RunningContainersForTargetForOnePod(iTarget, state)
+	require.NoError(t, err)
+	assert.Len(t, cInfos, 2)
+
+	iTarget = iTarget.WithBuildDetails(model.DockerBuild{LiveUpdate: model.LiveUpdate{ContainerName: "sancho"}})
+	cInfos, err = RunningContainersForTargetForOnePod(iTarget, state)
+	require.NoError(t, err)
+	if assert.Len(t, cInfos, 1) {
+		assert.Equal(t, container.ID("c-sancho"), cInfos[0].ContainerID)
+		assert.Equal(t, container.Name("sancho"), cInfos[0].ContainerName)
+	}
+
+	iTarget = iTarget.WithBuildDetails(model.DockerBuild{LiveUpdate: model.LiveUpdate{ContainerName: "missing"}})
+	cInfos, err = RunningContainersForTargetForOnePod(iTarget, state)
+	if assert.Error(t, err) {
+		assert.Equal(t, `live_update_container "missing" not found in pod sancho-pod (containers: "sancho", "sidecar")`, err.Error())
+	}
+	assert.Empty(t, cInfos)
+
+	// The pod hasn't reported its containers yet, so we can't tell.
+	state = NewK8sRuntimeStateWithPods(model.Manifest{}, v1alpha1.Pod{Name: "sancho-pod", Namespace: "default"})
+	cInfos, err = RunningContainersForTargetForOnePod(iTarget, state)
+	require.NoError(t, err)
+	assert.Empty(t, cInfos)
+}
+
+func TestRunningContainersForTargetForOnePodEphemeralContainer(t *testing.T) {
+	ref := container.MustParseSelector("gcr.io/some-project-162817/sancho")
+	running := v1alpha1.ContainerState{Running: &v1alpha1.ContainerStateRunning{}}
+	pod := v1alpha1.Pod{
+		Name:      "sancho-pod",
+		Namespace: "default",
+		Containers: []v1alpha1.Container{
+			{Name: "sancho", ID: "c-sancho", Image: "gcr.io/some-project-162817/sancho:tilt-123", State: running},
+		},
+		EphemeralContainers: []v1alpha1.Container{
+			{Name: "debugger", ID: "c-debugger", Image: "busybox", State: running},
+		},
+	}
+	state := NewK8sRuntimeStateWithPods(model.Manifest{}, pod)
+
+	// Ephemeral containers are only picked by name.
+	iTarget := model.MustNewImageTarget(ref).WithBuildDetails(model.DockerBuild{})
+	cInfos, err := RunningContainersForTargetForOnePod(iTarget, state)
+	require.NoError(t, err)
+	if assert.Len(t, cInfos, 1) {
+		assert.Equal(t, container.ID("c-sancho"), cInfos[0].ContainerID)
+	}
+
+	iTarget = iTarget.WithBuildDetails(model.DockerBuild{LiveUpdate: model.LiveUpdate{ContainerName: "debugger"}})
+	cInfos, err = RunningContainersForTargetForOnePod(iTarget, state)
+	require.NoError(t, err)
+	if assert.Len(t, cInfos, 1) {
+		assert.Equal(t, container.ID("c-debugger"), cInfos[0].ContainerID)
+		assert.Equal(t, container.Name("debugger"), cInfos[0].ContainerName)
+	}
+
+	// An ephemeral container that hasn't started yet can't be updated.
+	pod.EphemeralContainers[0].State = v1alpha1.ContainerState{Waiting: &v1alpha1.ContainerStateWaiting{Reason: "ContainerCreating"}}
+	state = NewK8sRuntimeStateWithPods(model.Manifest{}, pod)
+	cInfos, err = RunningContainersForTargetForOnePod(iTarget, state)
+	require.NoError(t, err)
+	assert.Empty(t, cInfos)
+}
@@ -407,6 +407,11 @@ type BuildStatus struct {
 	// dependency-tracking in the short-term, without having to switch over to a
 	// full dependency graph in one swoop.
 	PendingDependencyChanges map[model.TargetID]time.Time
+
+	// The files that changed while live updates were paused, and that we
+	// haven't synced. When live updates resume, we sync each file once, as it
+	// is then, instead of replaying every change.
+	PausedFileChanges map[string]bool
 }
 
 func newBuildStatus() *BuildStatus {
@@ -422,6 +427,29 @@ func (s BuildStatus) IsEmpty() bool {
 		s.LastResult == nil
 }
 
+func (s *BuildStatus) AddPausedFileChanges(files []string) {
+	if len(files) == 0 {
+		return
+	}
+	if s.PausedFileChanges == nil {
+		s.PausedFileChanges = make(map[string]bool)
+	}
+	for _, file := range files {
+		s.PausedFileChanges[file] = true
+	}
+}
+
+// Clear the pending changes to these files, e.g., because the build wrote
+// them itself and already handled them.
+func (s *BuildStatus) ClearPendingFileChangesBefore(files []string, t time.Time) {
+	for _, file := range files {
+		modTime, ok := s.PendingFileChanges[file]
+		if ok && timecmp.BeforeOrEqual(modTime, t) {
+			delete(s.PendingFileChanges, file)
+		}
+	}
+}
+
 func (s *BuildStatus) ClearPendingChangesBefore(startTime time.Time) {
 	for file, modTime := range s.PendingFileChanges {
 		if timecmp.BeforeOrEqual(modTime, startTime) {
@@ -464,6 +492,12 @@ type ManifestState struct {
 
 	// If the build was manually triggered, record why.
 	TriggerReason model.BuildReason
+
+	// If true, we skip live updates for this manifest's images, so that the user
+	// can poke around in a container without us syncing over them. Changes that
+	// need a full rebuild still get one. When live updates resume, we sync the
+	// files that changed while paused (see BuildStatus.PausedFileChanges).
+	LiveUpdatePaused bool
 }
 
 func NewState() *EngineState {
@@ -857,6 +891,7 @@ func StateToView(s EngineState, mu *sync.RWMutex) view.View {
 			CurrentBuild:       currentBuild,
 			Endpoints:          model.LinksToURLStrings(endpoints), // hud can't handle link names, just send URLs
 			ResourceInfo:       resourceInfoView(mt),
+			LiveUpdatePaused:   ms.LiveUpdatePaused,
 		}
 
 		ret.Resources = append(ret.Resources, r)
@@ -31,6 +31,8 @@ func Pod(ctx context.Context, pod *v1.Pod, ancestorUID types.UID) *v1alpha1.Pod
 		InitContainers: PodContainers(ctx, pod, pod.Status.InitContainerStatuses),
 		Containers:     PodContainers(ctx, pod, pod.Status.ContainerStatuses),
 
+		EphemeralContainers: PodContainers(ctx, pod, pod.Status.EphemeralContainerStatuses),
+
 		AncestorUID:         string(ancestorUID),
 		PodTemplateSpecHash: pod.Labels[k8s.TiltPodTemplateHashLabel],
 		Status:              PodStatusToString(*pod),
@@ -1,6 +1,7 @@
 package k8sconv
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -99,3 +100,27 @@ func TestPodStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestPodEphemeralContainers(t *testing.T) {
+	pod := &v1.Pod{
+		Status: v1.PodStatus{
+			EphemeralContainerStatuses: []v1.ContainerStatus{
+				{
+					Name:  "debugger",
+					Image: "busybox",
+					State: v1.ContainerState{
+						Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"},
+					},
+				},
+			},
+		},
+	}
+
+	p := Pod(context.Background(), pod, "")
+	if assert.Len(t, p.EphemeralContainers, 1) {
+		c := p.EphemeralContainers[0]
+		assert.Equal(t, "debugger", c.Name)
+		assert.Equal(t, v1alpha1.RuntimeStatusPending, ContainerStatusToRuntimeState(c))
+	}
+	assert.Empty(t, p.Containers)
+}
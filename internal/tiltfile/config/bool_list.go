@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"go.starlark.net/starlark"
+)
+
+type boolList struct {
+	Values []bool
+	isSet  bool
+}
+
+var _ configValue = &boolList{}
+var _ flag.Value = &boolList{}
+var _ listValue = &boolList{}
+
+func (s *boolList) starlark() starlark.Value {
+	var elems []starlark.Value
+	for _, v := range s.Values {
+		elems = append(elems, starlark.Bool(v))
+	}
+	return starlark.NewList(elems)
+}
+
+func (s *boolList) IsSet() bool {
+	return s.isSet
+}
+
+func (s *boolList) Len() int {
+	return len(s.Values)
+}
+
+func (s *boolList) Type() string {
+	return "list[bool]"
+}
+
+func (s *boolList) setFromInterface(i interface{}) error {
+	if i == nil {
+		s.Values = nil
+		return nil
+	}
+	is, ok := i.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected array")
+	}
+	s.Values = nil
+	for _, elem := range is {
+		b, ok := elem.(bool)
+		if !ok {
+			return fmt.Errorf("expected bool, got %T", elem)
+		}
+		s.Values = append(s.Values, b)
+	}
+
+	s.isSet = true
+	return nil
+}
+
+// Accepts either one value per flag (--foo true --foo false)
+// or several in one (--foo true,false or --foo "true false").
+func (s *boolList) Set(v string) error {
+	for _, elem := range splitListValue(v) {
+		b, err := strconv.ParseBool(elem)
+		if err != nil {
+			return fmt.Errorf("expected bool, found %q", elem)
+		}
+		s.Values = append(s.Values, b)
+	}
+	s.isSet = true
+	return nil
+}
+
+func (s *boolList) String() string {
+	var strs []string
+	for _, v := range s.Values {
+		strs = append(strs, strconv.FormatBool(v))
+	}
+	return strings.Join(strs, ",")
+}
@@ -23,6 +23,12 @@ type Settings struct {
 
 	// if parse has been called, the directory containing the Tiltfile that called it
 	seenWorkingDirectory string
+
+	// if parse has succeeded, where each setting's value came from
+	sources configSources
+
+	// if parse has succeeded, the merged settings it returned
+	config configMap
 }
 
 type Extension struct {
@@ -64,9 +70,17 @@ func (e *Extension) OnStart(env *starkit.Environment) error {
 	}{
 		{"config.set_enabled_resources", setEnabledResources},
 		{"config.parse", e.parse},
+		{"config.sources", e.sources},
+		{"config.to_json", e.toJSON},
 		{"config.define_string_list", configSettingDefinitionBuiltin(func() configValue {
 			return &stringList{}
 		})},
+		{"config.define_int_list", configSettingDefinitionBuiltin(func() configValue {
+			return &intList{}
+		})},
+		{"config.define_bool_list", configSettingDefinitionBuiltin(func() configValue {
+			return &boolList{}
+		})},
 		{"config.define_string", configSettingDefinitionBuiltin(func() configValue {
 			return &stringSetting{}
 		})},
@@ -76,6 +90,9 @@ func (e *Extension) OnStart(env *starkit.Environment) error {
 		{"config.define_object", configSettingDefinitionBuiltin(func() configValue {
 			return &objectSetting{}
 		})},
+		{"config.define_int_range", defineIntRange},
+		{"config.define_enum", defineEnum},
+		{"config.define_path", definePath},
 	} {
 		err := env.AddBuiltin(b.name, b.f)
 		if err != nil {
@@ -141,7 +158,7 @@ func (e *Extension) parse(thread *starlark.Thread, fn *starlark.Builtin, args st
 		return starlark.None, err
 	}
 
-	ret, out, err := settings.configDef.parse(userConfigPath, e.UserConfigState.Args)
+	config, sources, out, err := settings.configDef.parse(userConfigPath, e.UserConfigState.Args)
 	if out != "" {
 		thread.Print(thread, out)
 	}
@@ -149,5 +166,64 @@ func (e *Extension) parse(thread *starlark.Thread, fn *starlark.Builtin, args st
 		return starlark.None, err
 	}
 
-	return ret, nil
+	err = starkit.SetState(thread, func(settings Settings) (Settings, error) {
+		settings.sources = sources
+		settings.config = config
+		return settings, nil
+	})
+	if err != nil {
+		return starlark.None, err
+	}
+
+	return config.toStarlark()
+}
+
+// reports where each setting's value came from: "cli", "file", "env", or "default"
+func (e *Extension) sources(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	m, err := starkit.ModelFromThread(thread)
+	if err != nil {
+		return starlark.None, err
+	}
+	settings, err := GetState(m)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	if settings.sources == nil {
+		return starlark.None, fmt.Errorf("%s cannot be called before config.parse is called", fn.Name())
+	}
+
+	return settings.sources.toStarlark()
+}
+
+// returns the settings from config.parse as a JSON string (e.g., to pass to a subprocess)
+func (e *Extension) toJSON(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	m, err := starkit.ModelFromThread(thread)
+	if err != nil {
+		return starlark.None, err
+	}
+	settings, err := GetState(m)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	if settings.config == nil {
+		return starlark.None, fmt.Errorf("%s cannot be called before config.parse is called", fn.Name())
+	}
+
+	s, err := settings.config.toJSON()
+	if err != nil {
+		return starlark.None, errors.Wrap(err, fn.Name())
+	}
+	return starlark.String(s), nil
 }
@@ -2,14 +2,19 @@ package config
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
+	"github.com/schollz/closestmatch"
 	flag "github.com/spf13/pflag"
 	"go.starlark.net/starlark"
 
+	"github.com/tilt-dev/tilt/internal/tiltfile/encoding"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 )
 
@@ -20,11 +25,22 @@ type configValue interface {
 	IsSet() bool
 }
 
+// A configValue that holds a list of values (e.g., one that takes positional args).
+type listValue interface {
+	Len() int
+}
+
 type configMap map[string]configValue
 
 type configSetting struct {
 	newValue func() configValue
 	usage    string
+
+	// if set, the env var to read the setting from when it's not in args or the config file
+	envVar string
+
+	// if true, config.parse fails when the setting isn't set
+	required bool
 }
 
 type ConfigDef struct {
@@ -43,52 +59,173 @@ func (cm configMap) toStarlark() (starlark.Mapping, error) {
 	return ret, nil
 }
 
+// serializes the settings as a JSON object, with keys sorted so that the
+// output is stable across runs
+func (cm configMap) toJSON() (string, error) {
+	m := make(map[string]interface{}, len(cm))
+	for k, v := range cm {
+		data, err := encoding.ConvertStarlarkToStructuredData(v.starlark())
+		if err != nil {
+			return "", errors.Wrapf(err, "converting setting %s", k)
+		}
+		m[k] = data
+	}
+
+	// encoding/json sorts map keys
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// where each setting's value came from, for config.sources
+type configSources map[string]string
+
+const (
+	sourceCLI     = "cli"
+	sourceFile    = "file"
+	sourceEnv     = "env"
+	sourceDefault = "default"
+)
+
+func (cs configSources) toStarlark() (starlark.Mapping, error) {
+	var names []string
+	for name := range cs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ret := starlark.NewDict(len(cs))
+	for _, name := range names {
+		err := ret.SetKey(starlark.String(name), starlark.String(cs[name]))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
 // merges settings from config and settings from args, with settings from args trumping
-func mergeConfigMaps(settingsFromConfig, settingsFromArgs configMap) configMap {
+// also returns where each set value came from
+func mergeConfigMaps(settingsFromConfig, settingsFromArgs configMap) (configMap, configSources) {
 	ret := make(configMap)
+	sources := make(configSources)
 	for k, v := range settingsFromConfig {
 		ret[k] = v
+		if v.IsSet() {
+			sources[k] = sourceFile
+		}
 	}
 
 	for k, v := range settingsFromArgs {
 		if v.IsSet() {
 			ret[k] = v
+			sources[k] = sourceCLI
 		}
 	}
 
-	return ret
+	return ret, sources
 }
 
 // parse any args and merge them into the config
-func (cd ConfigDef) incorporateArgs(config configMap, args []string) (ret configMap, output string, err error) {
+func (cd ConfigDef) incorporateArgs(config configMap, args []string) (ret configMap, sources configSources, output string, err error) {
 	var settingsFromArgs configMap
 	settingsFromArgs, output, err = cd.parseArgs(args)
 	if err != nil {
-		return nil, output, err
+		return nil, nil, output, err
 	}
 
-	config = mergeConfigMaps(config, settingsFromArgs)
+	config, sources = mergeConfigMaps(config, settingsFromArgs)
 
-	return config, output, nil
+	return config, sources, output, nil
 }
 
-func (cd ConfigDef) parse(configPath string, args []string) (v starlark.Value, output string, err error) {
-	config, err := cd.readFromFile(configPath)
+func (cd ConfigDef) parse(configPath string, args []string) (config configMap, sources configSources, output string, err error) {
+	config, err = cd.readFromFile(configPath)
 	if err != nil {
-		return starlark.None, "", err
+		return nil, nil, "", err
 	}
 
-	config, output, err = cd.incorporateArgs(config, args)
+	config, sources, output, err = cd.incorporateArgs(config, args)
 	if err != nil {
-		return starlark.None, output, err
+		return nil, nil, output, err
 	}
 
-	ret, err := config.toStarlark()
+	config, err = cd.incorporateEnv(config, sources)
 	if err != nil {
-		return nil, output, err
+		return nil, nil, output, err
+	}
+
+	for name := range cd.configSettings {
+		if _, ok := sources[name]; !ok {
+			sources[name] = sourceDefault
+		}
+	}
+
+	err = cd.checkRequired(config)
+	if err != nil {
+		return nil, nil, output, err
+	}
+
+	return config, sources, output, nil
+}
+
+// fill in any settings that weren't in args or the config file from their env vars
+func (cd ConfigDef) incorporateEnv(config configMap, sources configSources) (configMap, error) {
+	for name, def := range cd.configSettings {
+		if def.envVar == "" {
+			continue
+		}
+		if v, ok := config[name]; ok && v.IsSet() {
+			continue
+		}
+
+		envValue, ok := os.LookupEnv(def.envVar)
+		if !ok {
+			continue
+		}
+
+		v := def.newValue()
+		err := v.Set(envValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "environment variable %s specified invalid value for setting %s", def.envVar, name)
+		}
+		config[name] = v
+		sources[name] = sourceEnv
+	}
+	return config, nil
+}
+
+// make sure every required setting was set somewhere
+func (cd ConfigDef) checkRequired(config configMap) error {
+	var missing []string
+	for name, def := range cd.configSettings {
+		if !def.required {
+			continue
+		}
+
+		v, ok := config[name]
+		if ok && v.IsSet() {
+			// a required positional list needs at least one value
+			l, isList := v.(listValue)
+			if name != cd.positionalSettingName || !isList || l.Len() > 0 {
+				continue
+			}
+		}
+
+		if name == cd.positionalSettingName {
+			missing = append(missing, fmt.Sprintf("%s (positional)", name))
+		} else {
+			missing = append(missing, name)
+		}
 	}
 
-	return ret, output, nil
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return fmt.Errorf("missing required config settings: %s", strings.Join(missing, ", "))
 }
 
 // parse command-line args
@@ -110,7 +247,10 @@ func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err
 		}
 	}
 
-	err = fs.Parse(args)
+	err = cd.checkFlagNames(args)
+	if err == nil {
+		err = fs.Parse(args)
+	}
 	if err != nil {
 		_, _ = fmt.Fprintf(w, "Error parsing tiltfile config args: %v\nUsage:\n", err)
 		fs.PrintDefaults()
@@ -119,7 +259,8 @@ func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err
 
 	if len(fs.Args()) > 0 {
 		if cd.positionalSettingName == "" {
-			return nil, w.String(), errors.New("positional args were specified, but none were expected (no setting defined with args=True)")
+			return nil, w.String(), fmt.Errorf("positional args were specified, but none were expected (no setting defined with args=True): %s",
+				strings.Join(fs.Args(), " "))
 		} else {
 			for _, arg := range fs.Args() {
 				err := ret[cd.positionalSettingName].Set(arg)
@@ -133,6 +274,62 @@ func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err
 	return ret, w.String(), nil
 }
 
+// Looks for flag-like args that don't name a setting, so that a typo'd
+// flag gets a better error than the FlagSet's "unknown flag".
+func (cd ConfigDef) checkFlagNames(args []string) error {
+	var names []string
+	for name := range cd.configSettings {
+		if name != cd.positionalSettingName {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			// everything after this is positional
+			return nil
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimLeft(arg, "-"), "=", 2)
+		name, hasValue := parts[0], len(parts) == 2
+		def, ok := cd.configSettings[name]
+
+		switch {
+		case !ok && (arg == "-h" || arg == "--help"):
+			// let the FlagSet print the usage
+		case ok && name == cd.positionalSettingName:
+			return fmt.Errorf("config setting %q takes positional args. Pass its values without --%s", name, name)
+		case ok && !strings.HasPrefix(arg, "--"):
+			return fmt.Errorf("unknown flag %q. Config settings take two dashes: --%s", arg, name)
+		case ok:
+			// skip a value passed as the next arg
+			if _, isBool := def.newValue().(*boolSetting); !isBool && !hasValue {
+				i++
+			}
+		default:
+			return unknownFlagError(arg, name, names)
+		}
+	}
+	return nil
+}
+
+func unknownFlagError(arg, name string, names []string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("unknown flag %q: no config settings are defined", arg)
+	}
+
+	cm := closestmatch.New(names, []int{2, 3, 4})
+	if match := cm.Closest(name); match != "" {
+		return fmt.Errorf("unknown flag %q. Did you mean --%s?", arg, match)
+	}
+	return fmt.Errorf("unknown flag %q. Defined config settings: --%s", arg, strings.Join(names, ", --"))
+}
+
 // parse settings from the config file
 func (cd ConfigDef) readFromFile(tiltConfigPath string) (ret configMap, err error) {
 	ret = make(configMap)
@@ -175,6 +372,8 @@ func configSettingDefinitionBuiltin(newConfigValue func() configValue) starkit.F
 		var name string
 		var isArgs bool
 		var usage string
+		var envVar string
+		var required bool
 		err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
 			"name",
 			&name,
@@ -182,43 +381,54 @@ func configSettingDefinitionBuiltin(newConfigValue func() configValue) starkit.F
 			&isArgs,
 			"usage?",
 			&usage,
+			"env_var?",
+			&envVar,
+			"required?",
+			&required,
 		)
 		if err != nil {
 			return starlark.None, err
 		}
 
-		if name == "" {
-			return starlark.None, errors.New("'name' is required")
+		err = defineConfigSetting(thread, fn, name, isArgs, configSetting{
+			newValue: newConfigValue,
+			usage:    usage,
+			envVar:   envVar,
+			required: required,
+		})
+		if err != nil {
+			return starlark.None, err
 		}
 
-		err = starkit.SetState(thread, func(settings Settings) (Settings, error) {
-			if settings.configParseCalled {
-				return settings, fmt.Errorf("%s cannot be called after config.parse is called", fn.Name())
-			}
+		return starlark.None, nil
+	}
+}
 
-			if _, ok := settings.configDef.configSettings[name]; ok {
-				return settings, fmt.Errorf("%s defined multiple times", name)
-			}
+// adds a setting to the ConfigDef, so that config.parse will look for it
+func defineConfigSetting(thread *starlark.Thread, fn *starlark.Builtin, name string, isArgs bool, setting configSetting) error {
+	if name == "" {
+		return errors.New("'name' is required")
+	}
 
-			if isArgs {
-				if settings.configDef.positionalSettingName != "" {
-					return settings, fmt.Errorf("both %s and %s are defined as positional args", name, settings.configDef.positionalSettingName)
-				}
+	return starkit.SetState(thread, func(settings Settings) (Settings, error) {
+		if settings.configParseCalled {
+			return settings, fmt.Errorf("%s cannot be called after config.parse is called", fn.Name())
+		}
 
-				settings.configDef.positionalSettingName = name
-			}
+		if _, ok := settings.configDef.configSettings[name]; ok {
+			return settings, fmt.Errorf("%s defined multiple times", name)
+		}
 
-			settings.configDef.configSettings[name] = configSetting{
-				newValue: newConfigValue,
-				usage:    usage,
+		if isArgs {
+			if settings.configDef.positionalSettingName != "" {
+				return settings, fmt.Errorf("both %s and %s are defined as positional args", name, settings.configDef.positionalSettingName)
 			}
 
-			return settings, nil
-		})
-		if err != nil {
-			return starlark.None, err
+			settings.configDef.positionalSettingName = name
 		}
 
-		return starlark.None, nil
-	}
+		settings.configDef.configSettings[name] = setting
+
+		return settings, nil
+	})
 }
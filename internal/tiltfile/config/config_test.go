@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 
@@ -201,7 +202,66 @@ config.parse()
 
 	_, err := f.ExecFile("Tiltfile")
 	require.Error(t, err)
-	require.Equal(t, "unknown flag: --bar", err.Error())
+	require.Equal(t, `unknown flag "--bar". Defined config settings: --foo`, err.Error())
+}
+
+func TestTypoedArg(t *testing.T) {
+	f := NewFixture(t, model.NewUserConfigState([]string{"--namespce=dev", "web"}), "")
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+config.define_string('namespace')
+config.define_string_list('to-run', args=True)
+config.parse()
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Equal(t, `unknown flag "--namespce=dev". Did you mean --namespace?`, err.Error())
+}
+
+func TestSingleDashArg(t *testing.T) {
+	f := NewFixture(t, model.NewUserConfigState([]string{"-foo", "hello"}), "")
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+config.define_string('foo')
+config.parse()
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Equal(t, `unknown flag "-foo". Config settings take two dashes: --foo`, err.Error())
+}
+
+func TestPositionalSettingPassedAsFlag(t *testing.T) {
+	f := NewFixture(t, model.NewUserConfigState([]string{"--foo", "hello"}), "")
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+config.define_string_list('foo', args=True)
+config.parse()
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Equal(t, `config setting "foo" takes positional args. Pass its values without --foo`, err.Error())
+}
+
+func TestFlagLikeArgValue(t *testing.T) {
+	f := NewFixture(t, model.NewUserConfigState([]string{"--foo", "--bar", "--", "--baz"}), "")
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+config.define_string('foo')
+config.define_string_list('rest', args=True)
+cfg = config.parse()
+print(cfg['foo'], cfg['rest'])
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Equal(t, "--bar [\"--baz\"]\n", f.PrintOutput())
 }
 
 func TestUnprovidedArg(t *testing.T) {
@@ -242,7 +302,7 @@ cfg = config.parse()
 
 	_, err := f.ExecFile("Tiltfile")
 	require.Error(t, err)
-	require.Equal(t, "positional args were specified, but none were expected (no setting defined with args=True)", err.Error())
+	require.Equal(t, "positional args were specified, but none were expected (no setting defined with args=True): do re mi", err.Error())
 }
 
 func TestUsage(t *testing.T) {
@@ -256,11 +316,32 @@ config.parse()
 
 	_, err := f.ExecFile("Tiltfile")
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "unknown flag: --bar")
+	require.Contains(t, err.Error(), `unknown flag "--bar"`)
 	require.Contains(t, f.PrintOutput(), "Usage:")
 	require.Contains(t, f.PrintOutput(), "what can I foo for you today")
 }
 
+func TestHelpFlag(t *testing.T) {
+	for _, arg := range []string{"--help", "-h"} {
+		t.Run(arg, func(t *testing.T) {
+			f := NewFixture(t, model.NewUserConfigState([]string{arg}), "")
+			defer f.TearDown()
+
+			f.File("Tiltfile", `
+config.define_string('foo', usage='what can I foo for you today?')
+config.parse()
+`)
+
+			_, err := f.ExecFile("Tiltfile")
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "help requested")
+			require.Contains(t, f.PrintOutput(), "Usage:")
+			require.Contains(t, f.PrintOutput(), "--foo")
+			require.Contains(t, f.PrintOutput(), "what can I foo for you today")
+		})
+	}
+}
+
 // i.e., tilt up foo bar gets you resources foo and bar
 func TestDefaultTiltBehavior(t *testing.T) {
 	f := NewFixture(t, model.NewUserConfigState([]string{"foo", "bar"}), "")
@@ -361,6 +442,125 @@ print("c=", cfg.get('c', 'missing'))
 	}
 }
 
+func TestSettingsFromEnvVar(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		args     []string
+		config   string
+		env      string
+		expected string
+	}{
+		{"default", nil, "", "", "missing"},
+		{"env only", nil, "", "env", "env"},
+		{"config trumps env", nil, `{"a": "config"}`, "env", "config"},
+		{"args trump env", []string{"--a", "args"}, "", "env", "args"},
+		{"args trump config and env", []string{"--a", "args"}, `{"a": "config"}`, "env", "args"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFixture(t, model.NewUserConfigState(tc.args), "")
+			defer f.TearDown()
+
+			envVar := "TILT_CONFIG_TEST_A"
+			if tc.env != "" {
+				require.NoError(t, os.Setenv(envVar, tc.env))
+			}
+			defer os.Unsetenv(envVar)
+
+			f.File("Tiltfile", fmt.Sprintf(`
+config.define_string('a', env_var='%s')
+cfg = config.parse()
+print("a=", cfg.get('a', 'missing'))
+`, envVar))
+			if tc.config != "" {
+				f.File(UserConfigFileName, tc.config)
+			}
+
+			_, err := f.ExecFile("Tiltfile")
+			require.NoError(t, err)
+			require.Contains(t, f.PrintOutput(), fmt.Sprintf("a= %s", tc.expected))
+		})
+	}
+}
+
+func TestInvalidSettingFromEnvVar(t *testing.T) {
+	f := NewFixture(t, model.UserConfigState{}, "")
+	defer f.TearDown()
+
+	require.NoError(t, os.Setenv("TILT_CONFIG_TEST_A", "10"))
+	defer os.Unsetenv("TILT_CONFIG_TEST_A")
+
+	f.File("Tiltfile", `
+config.define_int_range('a', 1, 5, env_var='TILT_CONFIG_TEST_A')
+cfg = config.parse()
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "environment variable TILT_CONFIG_TEST_A specified invalid value for setting a: value 10 is out of range [1, 5]")
+}
+
+func TestRequiredSettings(t *testing.T) {
+	for _, tc := range []struct {
+		name          string
+		args          []string
+		config        string
+		expectedError string
+	}{
+		{"all present", []string{"--a", "1", "x"}, "", ""},
+		{"present in config", []string{"x"}, `{"a": "1"}`, ""},
+		{"missing keyword", []string{"x"}, "", "missing required config settings: a"},
+		{"missing positional", []string{"--a", "1"}, "", "missing required config settings: b (positional)"},
+		{"empty positional from config", []string{"--a", "1"}, `{"b": []}`, "missing required config settings: b (positional)"},
+		{"missing both", nil, "", "missing required config settings: a, b (positional)"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := NewFixture(t, model.NewUserConfigState(tc.args), "")
+			defer f.TearDown()
+
+			f.File("Tiltfile", `
+config.define_string('a', required=True)
+config.define_string_list('b', args=True, required=True)
+config.define_string('c')
+cfg = config.parse()
+`)
+			if tc.config != "" {
+				f.File(UserConfigFileName, tc.config)
+			}
+
+			_, err := f.ExecFile("Tiltfile")
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestRequiredPositionalNonStringList(t *testing.T) {
+	for _, typ := range []string{"int_list", "bool_list"} {
+		for _, config := range []string{"", `{"b": []}`} {
+			t.Run(fmt.Sprintf("%s %s", typ, config), func(t *testing.T) {
+				f := NewFixture(t, model.NewUserConfigState(nil), "")
+				defer f.TearDown()
+
+				f.File("Tiltfile", fmt.Sprintf(`
+config.define_%s('b', args=True, required=True)
+cfg = config.parse()
+`, typ))
+				if config != "" {
+					f.File(UserConfigFileName, config)
+				}
+
+				_, err := f.ExecFile("Tiltfile")
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "missing required config settings: b (positional)")
+			})
+		}
+	}
+}
+
 func TestUndefinedArgInConfigFile(t *testing.T) {
 	f := NewFixture(t, model.UserConfigState{}, "")
 	defer f.TearDown()
@@ -497,6 +697,151 @@ print(config.main_dir)
 	require.Equal(t, fmt.Sprintf("%s\n%s\n", val, val), f.PrintOutput())
 }
 
+func TestPathRelativeToTiltfileDir(t *testing.T) {
+	f := NewFixture(t, model.UserConfigState{Args: []string{"--foo", "bar/baz.txt"}}, "")
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+config.define_path('foo')
+cfg = config.parse()
+print(cfg['foo'])
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	require.Equal(t, f.JoinPath("bar", "baz.txt")+"\n", f.PrintOutput())
+}
+
+func TestPathAbsoluteIsUnchanged(t *testing.T) {
+	f := NewFixture(t, model.UserConfigState{}, "")
+	defer f.TearDown()
+
+	abs := f.JoinPath("elsewhere", "baz.txt")
+	f.File("tilt_config.json", fmt.Sprintf(`{"foo": %q}`, abs))
+	f.File("Tiltfile", `
+config.define_path('foo')
+cfg = config.parse()
+print(cfg['foo'])
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	require.Equal(t, abs+"\n", f.PrintOutput())
+}
+
+func TestSources(t *testing.T) {
+	f := NewFixture(t, model.UserConfigState{Args: []string{"--a", "1", "--b", "2"}}, "")
+	defer f.TearDown()
+
+	require.NoError(t, os.Setenv("TILT_CONFIG_TEST_D", "4"))
+	defer os.Unsetenv("TILT_CONFIG_TEST_D")
+
+	f.File("tilt_config.json", `{"b": "3", "c": "3"}`)
+	f.File("Tiltfile", `
+config.define_string('a')
+config.define_string('b')
+config.define_string('c')
+config.define_string('d', env_var='TILT_CONFIG_TEST_D')
+config.define_string('e')
+cfg = config.parse()
+print(config.sources())
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	require.Equal(t, `{"a": "cli", "b": "cli", "c": "file", "d": "env", "e": "default"}`+"\n", f.PrintOutput())
+}
+
+func TestSourcesBeforeParse(t *testing.T) {
+	f := NewFixture(t, model.UserConfigState{}, "")
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+config.define_string('a')
+config.sources()
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "config.sources cannot be called before config.parse is called")
+}
+
+func TestToJSON(t *testing.T) {
+	f := NewFixture(t, model.UserConfigState{Args: []string{"--b", "x", "--a", "1", "--a", "2", "--d"}}, "")
+	defer f.TearDown()
+
+	f.File("tilt_config.json", `{"c": {"k": ["v"]}}`)
+	f.File("Tiltfile", `
+config.define_string('b')
+config.define_int_list('a')
+config.define_object('c')
+config.define_bool('d')
+config.define_string('e')
+config.parse()
+print(config.to_json())
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	// unset settings are omitted, just like in config.parse's result
+	require.Equal(t, `{"a":[1,2],"b":"x","c":{"k":["v"]},"d":true}`+"\n", f.PrintOutput())
+}
+
+func TestToJSONRoundTrip(t *testing.T) {
+	tf := `
+config.define_string_list('a')
+config.define_int_range('b', 0, 10)
+config.define_bool_list('c')
+config.define_object('d')
+config.define_path('e')
+config.define_enum('f', ['x', 'y'])
+config.parse()
+print(config.to_json())
+`
+	f := NewFixture(t, model.UserConfigState{Args: []string{
+		"--a", "hello", "--a", "world",
+		"--b", "3",
+		"--c", "true,false",
+		"--d", `{"k": [1, "two", null]}`,
+		"--e", "/src",
+		"--f", "y",
+	}}, "")
+	defer f.TearDown()
+
+	f.File("Tiltfile", tf)
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	out := f.PrintOutput()
+
+	// Feeding the output back in as the config file should produce the same settings.
+	f2 := NewFixture(t, model.UserConfigState{}, "")
+	defer f2.TearDown()
+
+	f2.File("tilt_config.json", out)
+	f2.File("Tiltfile", tf)
+	_, err = f2.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Equal(t, out, f2.PrintOutput())
+}
+
+func TestToJSONBeforeParse(t *testing.T) {
+	f := NewFixture(t, model.UserConfigState{}, "")
+	defer f.TearDown()
+
+	f.File("Tiltfile", `
+config.define_string('a')
+config.to_json()
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "config.to_json cannot be called before config.parse is called")
+}
+
 func NewFixture(tb testing.TB, userConfigState model.UserConfigState, tiltSubcommand model.TiltSubcommand) *starkit.Fixture {
 	ext := NewExtension(tiltSubcommand)
 	ext.UserConfigState = userConfigState
@@ -554,6 +899,20 @@ func TestTypes(t *testing.T) {
 		newTypeTestCase("string_list from config", "config.define_string_list('foo')").withConfigFile(`{"foo": ["1", "2"]}`).withExpectedVal("['1', '2']"),
 		newTypeTestCase("invalid string_list from config", "config.define_string_list('foo')").withConfigFile(`{"foo": [1, 2]}`).withExpectedError("expected string, got float64"),
 
+		newTypeTestCase("int_list from args", "config.define_int_list('foo')").withArgs("--foo", "8080", "--foo", "9090").withExpectedVal("[8080, 9090]"),
+		newTypeTestCase("int_list from comma-separated args", "config.define_int_list('foo')").withArgs("--foo=8080,9090").withExpectedVal("[8080, 9090]"),
+		newTypeTestCase("int_list from space-separated args", "config.define_int_list('foo')").withArgs("--foo", "8080 9090").withExpectedVal("[8080, 9090]"),
+		newTypeTestCase("int_list positional", "config.define_int_list('foo', args=True)").withArgs("1", "2,3").withExpectedVal("[1, 2, 3]"),
+		newTypeTestCase("int_list from config", "config.define_int_list('foo')").withConfigFile(`{"foo": [1, 2]}`).withExpectedVal("[1, 2]"),
+		newTypeTestCase("non-numeric int_list from args", "config.define_int_list('foo')").withArgs("--foo", "1,bar").withExpectedError(`expected int, found "bar"`),
+		newTypeTestCase("invalid int_list from config", "config.define_int_list('foo')").withConfigFile(`{"foo": [1, "2"]}`).withExpectedError("expected int, got string"),
+		newTypeTestCase("non-integral int_list from config", "config.define_int_list('foo')").withConfigFile(`{"foo": [1.5]}`).withExpectedError("invalid value for setting foo: expected int, got 1.5"),
+
+		newTypeTestCase("bool_list from args", "config.define_bool_list('foo')").withArgs("--foo", "true,false", "--foo", "true").withExpectedVal("[True, False, True]"),
+		newTypeTestCase("bool_list from config", "config.define_bool_list('foo')").withConfigFile(`{"foo": [true, false]}`).withExpectedVal("[True, False]"),
+		newTypeTestCase("invalid bool_list from args", "config.define_bool_list('foo')").withArgs("--foo", "true,maybe").withExpectedError(`expected bool, found "maybe"`),
+		newTypeTestCase("invalid bool_list from config", "config.define_bool_list('foo')").withConfigFile(`{"foo": [1]}`).withExpectedError("expected bool, got float64"),
+
 		newTypeTestCase("string from args", "config.define_string('foo')").withArgs("--foo", "bar").withExpectedVal("'bar'"),
 		newTypeTestCase("string from config", "config.define_string('foo')").withConfigFile(`{"foo": "bar"}`).withExpectedVal("'bar'"),
 		newTypeTestCase("string defined multiple times", "config.define_string('foo')").withArgs("--foo", "bar", "--foo", "baz").withExpectedError("string settings can only be specified once"),
@@ -564,6 +923,31 @@ func TestTypes(t *testing.T) {
 		newTypeTestCase("bool defined multiple times", "config.define_bool('foo')").withArgs("--foo", "--foo").withExpectedError("bool settings can only be specified once"),
 		newTypeTestCase("invalid bool from config", "config.define_bool('foo')").withConfigFile(`{"foo": 5}`).withExpectedError("expected bool, found float64"),
 
+		newTypeTestCase("int_range from args", "config.define_int_range('foo', 1, 5)").withArgs("--foo", "3").withExpectedVal("3"),
+		newTypeTestCase("int_range from config", "config.define_int_range('foo', 1, 5)").withConfigFile(`{"foo": 5}`).withExpectedVal("5"),
+		newTypeTestCase("int_range positional", "config.define_int_range('foo', 1, 5, args=True)").withArgs("1").withExpectedVal("1"),
+		newTypeTestCase("int_range below min from args", "config.define_int_range('foo', 1, 5)").withArgs("--foo", "0").withExpectedError("value 0 is out of range [1, 5]"),
+		newTypeTestCase("int_range above max from args", "config.define_int_range('foo', 1, 5)").withArgs("--foo", "6").withExpectedError("value 6 is out of range [1, 5]"),
+		newTypeTestCase("int_range above max from config", "config.define_int_range('foo', 1, 5)").withConfigFile(`{"foo": 6}`).withExpectedError("value 6 is out of range [1, 5]"),
+		newTypeTestCase("int_range defined multiple times", "config.define_int_range('foo', 1, 5)").withArgs("--foo", "2", "--foo", "3").withExpectedError("int settings can only be specified once"),
+		newTypeTestCase("invalid int_range from args", "config.define_int_range('foo', 1, 5)").withArgs("--foo", "bar").withExpectedError(`expected int, found "bar"`),
+		newTypeTestCase("invalid int_range from config", "config.define_int_range('foo', 1, 5)").withConfigFile(`{"foo": 2.5}`).withExpectedError("expected int, found float64"),
+		newTypeTestCase("int_range with min > max", "config.define_int_range('foo', 5, 1)").withExpectedError("min (5) must be <= max (1)"),
+
+		newTypeTestCase("enum from args", "config.define_enum('foo', values=['dev', 'staging', 'prod'])").withArgs("--foo", "staging").withExpectedVal("'staging'"),
+		newTypeTestCase("enum from config", "config.define_enum('foo', values=['dev', 'staging', 'prod'])").withConfigFile(`{"foo": "prod"}`).withExpectedVal("'prod'"),
+		newTypeTestCase("unknown enum from args", "config.define_enum('foo', values=['dev', 'staging', 'prod'])").withArgs("--foo", "qa").withExpectedError(`invalid value "qa". must be one of: dev, staging, prod`),
+		newTypeTestCase("unknown enum from config", "config.define_enum('foo', values=['dev', 'staging', 'prod'])").withConfigFile(`{"foo": "qa"}`).withExpectedError(`invalid value "qa". must be one of: dev, staging, prod`),
+		newTypeTestCase("invalid enum from config", "config.define_enum('foo', values=['dev'])").withConfigFile(`{"foo": 5}`).withExpectedError("expected string, found float64"),
+		newTypeTestCase("enum defined multiple times", "config.define_enum('foo', values=['dev', 'prod'])").withArgs("--foo", "dev", "--foo", "prod").withExpectedError("enum settings can only be specified once"),
+		newTypeTestCase("enum with no values", "config.define_enum('foo', values=[])").withExpectedError("'values' must not be empty"),
+
+		newTypeTestCase("path must_exist missing from args", "config.define_path('foo', must_exist=True)").withArgs("--foo", "missing").withExpectedError("missing\" does not exist"),
+		newTypeTestCase("path must_exist missing from config", "config.define_path('foo', must_exist=True)").withConfigFile(`{"foo": "missing"}`).withExpectedError("missing\" does not exist"),
+		newTypeTestCase("path must_exist present", "config.define_path('foo', must_exist=True)").withArgs("--foo", "Tiltfile").withExpectedVal("config.main_dir + '/Tiltfile'"),
+		newTypeTestCase("invalid path from config", "config.define_path('foo')").withConfigFile(`{"foo": 5}`).withExpectedError("expected string, found float64"),
+		newTypeTestCase("path defined multiple times", "config.define_path('foo')").withArgs("--foo", "a", "--foo", "b").withExpectedError("path settings can only be specified once"),
+
 		newTypeTestCase("obj from args", "config.define_object('foo')").
 			withArgs(`--foo`, `["a", "b", "c"]`).
 			withExpectedVal(`["a", "b", "c"]`),
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+)
+
+// A string setting that must be one of a fixed set of values
+type enumSetting struct {
+	values []string
+	value  string
+	isSet  bool
+}
+
+var _ configValue = &enumSetting{}
+var _ flag.Value = &enumSetting{}
+
+func (s *enumSetting) starlark() starlark.Value {
+	return starlark.String(s.value)
+}
+
+func (s *enumSetting) IsSet() bool {
+	return s.isSet
+}
+
+func (s *enumSetting) Type() string {
+	return "string"
+}
+
+func (s *enumSetting) checkValue(v string) error {
+	for _, allowed := range s.values {
+		if v == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value %q. must be one of: %s", v, strings.Join(s.values, ", "))
+}
+
+func (s *enumSetting) setFromInterface(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	v, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("expected %T, found %T", s.value, i)
+	}
+
+	err := s.checkValue(v)
+	if err != nil {
+		return err
+	}
+
+	s.value = v
+	s.isSet = true
+
+	return nil
+}
+
+func (s *enumSetting) Set(v string) error {
+	if s.isSet {
+		return fmt.Errorf("enum settings can only be specified once. multiple values found (last value: %s)", v)
+	}
+
+	err := s.checkValue(v)
+	if err != nil {
+		return err
+	}
+
+	s.value = v
+	s.isSet = true
+	return nil
+}
+
+func (s *enumSetting) String() string {
+	return s.value
+}
+
+func defineEnum(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var values value.StringOrStringList
+	var isArgs bool
+	var usage string
+	var envVar string
+	var required bool
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"name",
+		&name,
+		"values",
+		&values,
+		"args?",
+		&isArgs,
+		"usage?",
+		&usage,
+		"env_var?",
+		&envVar,
+		"required?",
+		&required,
+	)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	if len(values.Values) == 0 {
+		return starlark.None, fmt.Errorf("%s: 'values' must not be empty", fn.Name())
+	}
+
+	err = defineConfigSetting(thread, fn, name, isArgs, configSetting{
+		newValue: func() configValue {
+			return &enumSetting{values: values.Values}
+		},
+		usage:    usage,
+		envVar:   envVar,
+		required: required,
+	})
+	if err != nil {
+		return starlark.None, err
+	}
+
+	return starlark.None, nil
+}
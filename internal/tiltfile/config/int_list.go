@@ -0,0 +1,96 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	flag "github.com/spf13/pflag"
+	"go.starlark.net/starlark"
+)
+
+type intList struct {
+	Values []int
+	isSet  bool
+}
+
+var _ configValue = &intList{}
+var _ flag.Value = &intList{}
+var _ listValue = &intList{}
+
+func (s *intList) starlark() starlark.Value {
+	var elems []starlark.Value
+	for _, v := range s.Values {
+		elems = append(elems, starlark.MakeInt(v))
+	}
+	return starlark.NewList(elems)
+}
+
+func (s *intList) IsSet() bool {
+	return s.isSet
+}
+
+func (s *intList) Len() int {
+	return len(s.Values)
+}
+
+func (s *intList) Type() string {
+	return "list[int]"
+}
+
+func (s *intList) setFromInterface(i interface{}) error {
+	if i == nil {
+		s.Values = nil
+		return nil
+	}
+	is, ok := i.([]interface{})
+	if !ok {
+		return fmt.Errorf("expected array")
+	}
+	s.Values = nil
+	for _, elem := range is {
+		// json numbers are decoded as float64
+		f, ok := elem.(float64)
+		if !ok {
+			return fmt.Errorf("expected int, got %T", elem)
+		}
+		if f != math.Trunc(f) {
+			return fmt.Errorf("expected int, got %v", f)
+		}
+		s.Values = append(s.Values, int(f))
+	}
+
+	s.isSet = true
+	return nil
+}
+
+// Accepts either one value per flag (--foo 1 --foo 2)
+// or several in one (--foo 1,2 or --foo "1 2").
+func (s *intList) Set(v string) error {
+	for _, elem := range splitListValue(v) {
+		i, err := strconv.Atoi(elem)
+		if err != nil {
+			return fmt.Errorf("expected int, found %q", elem)
+		}
+		s.Values = append(s.Values, i)
+	}
+	s.isSet = true
+	return nil
+}
+
+func (s *intList) String() string {
+	var strs []string
+	for _, v := range s.Values {
+		strs = append(strs, strconv.Itoa(v))
+	}
+	return strings.Join(strs, ",")
+}
+
+// splits a list flag value on commas and whitespace
+func splitListValue(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	flag "github.com/spf13/pflag"
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+)
+
+// An int setting that must fall within [min, max]
+type intRangeSetting struct {
+	min   int
+	max   int
+	value int
+	isSet bool
+}
+
+var _ configValue = &intRangeSetting{}
+var _ flag.Value = &intRangeSetting{}
+
+func (s *intRangeSetting) starlark() starlark.Value {
+	return starlark.MakeInt(s.value)
+}
+
+func (s *intRangeSetting) IsSet() bool {
+	return s.isSet
+}
+
+func (s *intRangeSetting) Type() string {
+	return "int"
+}
+
+func (s *intRangeSetting) checkRange(v int) error {
+	if v < s.min || v > s.max {
+		return fmt.Errorf("value %d is out of range [%d, %d]", v, s.min, s.max)
+	}
+	return nil
+}
+
+func (s *intRangeSetting) setFromInterface(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	// json numbers are decoded as float64
+	f, ok := i.(float64)
+	if !ok || f != math.Trunc(f) {
+		return fmt.Errorf("expected int, found %T", i)
+	}
+
+	v := int(f)
+	err := s.checkRange(v)
+	if err != nil {
+		return err
+	}
+
+	s.value = v
+	s.isSet = true
+
+	return nil
+}
+
+func (s *intRangeSetting) Set(v string) error {
+	if s.isSet {
+		return fmt.Errorf("int settings can only be specified once. multiple values found (last value: %s)", v)
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("expected int, found %q", v)
+	}
+	err = s.checkRange(i)
+	if err != nil {
+		return err
+	}
+	s.value = i
+	s.isSet = true
+	return nil
+}
+
+func (s *intRangeSetting) String() string {
+	return strconv.Itoa(s.value)
+}
+
+func defineIntRange(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var min, max int
+	var isArgs bool
+	var usage string
+	var envVar string
+	var required bool
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"name",
+		&name,
+		"min",
+		&min,
+		"max",
+		&max,
+		"args?",
+		&isArgs,
+		"usage?",
+		&usage,
+		"env_var?",
+		&envVar,
+		"required?",
+		&required,
+	)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	if min > max {
+		return starlark.None, fmt.Errorf("%s: min (%d) must be <= max (%d)", fn.Name(), min, max)
+	}
+
+	err = defineConfigSetting(thread, fn, name, isArgs, configSetting{
+		newValue: func() configValue {
+			return &intRangeSetting{min: min, max: max}
+		},
+		usage:    usage,
+		envVar:   envVar,
+		required: required,
+	})
+	if err != nil {
+		return starlark.None, err
+	}
+
+	return starlark.None, nil
+}
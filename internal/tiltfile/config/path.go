@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	flag "github.com/spf13/pflag"
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+)
+
+// A string setting that's a path on disk. Relative paths are resolved
+// against the directory of the Tiltfile that defined the setting.
+type pathSetting struct {
+	baseDir   string
+	mustExist bool
+	value     string
+	isSet     bool
+}
+
+var _ configValue = &pathSetting{}
+var _ flag.Value = &pathSetting{}
+
+func (s *pathSetting) starlark() starlark.Value {
+	return starlark.String(s.value)
+}
+
+func (s *pathSetting) IsSet() bool {
+	return s.isSet
+}
+
+func (s *pathSetting) Type() string {
+	return "path"
+}
+
+func (s *pathSetting) resolve(v string) (string, error) {
+	p := v
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(s.baseDir, p)
+	}
+
+	if s.mustExist {
+		_, err := os.Stat(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return "", fmt.Errorf("path %q does not exist", p)
+			}
+			return "", err
+		}
+	}
+
+	return p, nil
+}
+
+func (s *pathSetting) setFromInterface(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	v, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("expected string, found %T", i)
+	}
+
+	p, err := s.resolve(v)
+	if err != nil {
+		return err
+	}
+
+	s.value = p
+	s.isSet = true
+
+	return nil
+}
+
+func (s *pathSetting) Set(v string) error {
+	if s.isSet {
+		return fmt.Errorf("path settings can only be specified once. multiple values found (last value: %s)", v)
+	}
+
+	p, err := s.resolve(v)
+	if err != nil {
+		return err
+	}
+
+	s.value = p
+	s.isSet = true
+	return nil
+}
+
+func (s *pathSetting) String() string {
+	return s.value
+}
+
+func definePath(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var isArgs bool
+	var usage string
+	var envVar string
+	var required bool
+	var mustExist bool
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"name",
+		&name,
+		"args?",
+		&isArgs,
+		"usage?",
+		&usage,
+		"env_var?",
+		&envVar,
+		"required?",
+		&required,
+		"must_exist?",
+		&mustExist,
+	)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	baseDir := starkit.AbsWorkingDir(thread)
+	err = defineConfigSetting(thread, fn, name, isArgs, configSetting{
+		newValue: func() configValue {
+			return &pathSetting{baseDir: baseDir, mustExist: mustExist}
+		},
+		usage:    usage,
+		envVar:   envVar,
+		required: required,
+	})
+	if err != nil {
+		return starlark.None, err
+	}
+
+	return starlark.None, nil
+}
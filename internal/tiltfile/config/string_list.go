@@ -17,6 +17,7 @@ type stringList struct {
 
 var _ configValue = &stringList{}
 var _ flag.Value = &stringList{}
+var _ listValue = &stringList{}
 
 func (s *stringList) starlark() starlark.Value {
 	return value.StringSliceToList(s.Values)
@@ -26,6 +27,10 @@ func (s *stringList) IsSet() bool {
 	return s.isSet
 }
 
+func (s *stringList) Len() int {
+	return len(s.Values)
+}
+
 func (s *stringList) Type() string {
 	return "list[string]"
 }
@@ -92,7 +92,7 @@ func (d *dockerImage) Type() dockerImageBuildType {
 }
 
 func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-	var dockerRef, targetStage string
+	var dockerRef, targetStage, liveUpdateUpdater, liveUpdateContainer string
 	var contextVal,
 		dockerfilePathVal,
 		dockerfileContentsVal,
@@ -114,6 +114,8 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		"dockerfile_contents?", &dockerfileContentsVal,
 		"cache?", &cacheVal,
 		"live_update?", &liveUpdateVal,
+		"live_update_updater?", &liveUpdateUpdater,
+		"live_update_container?", &liveUpdateContainer,
 		"match_in_env_vars?", &matchInEnvVars,
 		"ignore?", &ignoreVal,
 		"only?", &onlyVal,
@@ -184,6 +186,11 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 	if err != nil {
 		return nil, errors.Wrap(err, "live_update")
 	}
+	liveUpdate.Updater, err = model.ParseLiveUpdateUpdater(liveUpdateUpdater)
+	if err != nil {
+		return nil, errors.Wrap(err, "live_update_updater")
+	}
+	liveUpdate.ContainerName = liveUpdateContainer
 
 	ignores, err := parseValuesToStrings(ignoreVal, "ignore")
 	if err != nil {
@@ -263,7 +270,7 @@ func (s *tiltfileState) parseOnly(val starlark.Value) ([]string, error) {
 }
 
 func (s *tiltfileState) customBuild(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-	var dockerRef string
+	var dockerRef, liveUpdateUpdater, liveUpdateContainer string
 	var commandVal, commandBat, commandBatVal starlark.Value
 	deps := value.NewLocalPathListUnpacker(thread)
 	var tag string
@@ -283,6 +290,8 @@ func (s *tiltfileState) customBuild(thread *starlark.Thread, fn *starlark.Builti
 		"disable_push?", &disablePush,
 		"skips_local_docker?", &skipsLocalDocker,
 		"live_update?", &liveUpdateVal,
+		"live_update_updater?", &liveUpdateUpdater,
+		"live_update_container?", &liveUpdateContainer,
 		"match_in_env_vars?", &matchInEnvVars,
 		"ignore?", &ignoreVal,
 		"entrypoint?", &entrypoint,
@@ -307,6 +316,11 @@ func (s *tiltfileState) customBuild(thread *starlark.Thread, fn *starlark.Builti
 	if err != nil {
 		return nil, errors.Wrap(err, "live_update")
 	}
+	liveUpdate.Updater, err = model.ParseLiveUpdateUpdater(liveUpdateUpdater)
+	if err != nil {
+		return nil, errors.Wrap(err, "live_update_updater")
+	}
+	liveUpdate.ContainerName = liveUpdateContainer
 
 	ignores, err := parseValuesToStrings(ignoreVal, "ignore")
 	if err != nil {
@@ -85,7 +85,7 @@ func encodeJSON(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tup
 }
 
 func starlarkToJSONString(obj starlark.Value) (string, error) {
-	v, err := convertStarlarkToStructuredData(obj)
+	v, err := ConvertStarlarkToStructuredData(obj)
 	if err != nil {
 		return "", errors.Wrap(err, "error converting object from starlark")
 	}
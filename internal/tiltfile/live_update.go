@@ -2,6 +2,7 @@ package tiltfile
 
 import (
 	"fmt"
+	"os"
 	"path"
 	"strconv"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"go.starlark.net/starlark"
 
+	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -57,8 +59,31 @@ func (l liveUpdateFallBackOnStep) Hash() (uint32, error) {
 func (l liveUpdateFallBackOnStep) liveUpdateStep()        {}
 func (l liveUpdateFallBackOnStep) declarationPos() string { return l.position.String() }
 
+type liveUpdateLocalPreSyncStep struct {
+	command  model.Cmd
+	position syntax.Position
+}
+
+var _ starlark.Value = liveUpdateLocalPreSyncStep{}
+var _ liveUpdateStep = liveUpdateLocalPreSyncStep{}
+
+func (l liveUpdateLocalPreSyncStep) String() string {
+	return fmt.Sprintf("local_pre_sync step: %s", strconv.Quote(l.command.String()))
+}
+func (l liveUpdateLocalPreSyncStep) Type() string { return "live_update_local_pre_sync_step" }
+func (l liveUpdateLocalPreSyncStep) Freeze()      {}
+func (l liveUpdateLocalPreSyncStep) Truth() starlark.Bool {
+	return starlark.Bool(!l.command.Empty())
+}
+func (l liveUpdateLocalPreSyncStep) Hash() (uint32, error) {
+	return starlark.Tuple{starlark.String(l.command.String())}.Hash()
+}
+func (l liveUpdateLocalPreSyncStep) liveUpdateStep()        {}
+func (l liveUpdateLocalPreSyncStep) declarationPos() string { return l.position.String() }
+
 type liveUpdateSyncStep struct {
 	localPath, remotePath string
+	mustExist             bool
 	position              syntax.Position
 }
 
@@ -80,9 +105,10 @@ func (l liveUpdateSyncStep) liveUpdateStep()        {}
 func (l liveUpdateSyncStep) declarationPos() string { return l.position.String() }
 
 type liveUpdateRunStep struct {
-	command  model.Cmd
-	triggers []string
-	position syntax.Position
+	command     model.Cmd
+	triggers    []string
+	forEachFile bool
+	position    syntax.Position
 }
 
 var _ starlark.Value = liveUpdateRunStep{}
@@ -127,12 +153,31 @@ func (l liveUpdateRestartContainerStep) Hash() (uint32, error)  { return 0, nil
 func (l liveUpdateRestartContainerStep) declarationPos() string { return l.position.String() }
 func (l liveUpdateRestartContainerStep) liveUpdateStep()        {}
 
+type liveUpdateSignalProcessStep struct {
+	signal   string
+	position syntax.Position
+}
+
+var _ starlark.Value = liveUpdateSignalProcessStep{}
+var _ liveUpdateStep = liveUpdateSignalProcessStep{}
+
+func (l liveUpdateSignalProcessStep) String() string {
+	return fmt.Sprintf("signal_process step: SIG%s", l.signal)
+}
+func (l liveUpdateSignalProcessStep) Type() string           { return "live_update_signal_process_step" }
+func (l liveUpdateSignalProcessStep) Freeze()                {}
+func (l liveUpdateSignalProcessStep) Truth() starlark.Bool   { return true }
+func (l liveUpdateSignalProcessStep) Hash() (uint32, error)  { return starlark.String(l.signal).Hash() }
+func (l liveUpdateSignalProcessStep) declarationPos() string { return l.position.String() }
+func (l liveUpdateSignalProcessStep) liveUpdateStep()        {}
+
 func (s *tiltfileState) recordLiveUpdateStep(step liveUpdateStep) {
 	s.unconsumedLiveUpdateSteps[step.declarationPos()] = step
 }
 
 func (s *tiltfileState) liveUpdateFallBackOn(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-	files := value.NewLocalPathListUnpacker(thread)
+	// Paths may be globs, including `!` exclusions
+	files := value.NewLocalPatternListUnpacker(thread)
 	if err := s.unpackArgs(fn.Name(), args, kwargs, "paths", &files); err != nil {
 		return nil, err
 	}
@@ -145,15 +190,39 @@ func (s *tiltfileState) liveUpdateFallBackOn(thread *starlark.Thread, fn *starla
 	return ret, nil
 }
 
+func (s *tiltfileState) liveUpdateLocalPreSync(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var commandVal starlark.Value
+	if err := s.unpackArgs(fn.Name(), args, kwargs, "cmd", &commandVal); err != nil {
+		return nil, err
+	}
+
+	command, err := value.ValueToHostCmd(thread, commandVal, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := liveUpdateLocalPreSyncStep{
+		command:  command,
+		position: thread.CallFrame(1).Pos,
+	}
+	s.recordLiveUpdateStep(ret)
+	return ret, nil
+}
+
 func (s *tiltfileState) liveUpdateSync(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var localPath, remotePath string
-	if err := s.unpackArgs(fn.Name(), args, kwargs, "local_path", &localPath, "remote_path", &remotePath); err != nil {
+	var mustExist bool
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"local_path", &localPath,
+		"remote_path", &remotePath,
+		"must_exist?", &mustExist); err != nil {
 		return nil, err
 	}
 
 	ret := liveUpdateSyncStep{
 		localPath:  starkit.AbsPath(thread, localPath),
 		remotePath: remotePath,
+		mustExist:  mustExist,
 		position:   thread.CallFrame(1).Pos,
 	}
 	s.recordLiveUpdateStep(ret)
@@ -163,9 +232,11 @@ func (s *tiltfileState) liveUpdateSync(thread *starlark.Thread, fn *starlark.Bui
 func (s *tiltfileState) liveUpdateRun(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var commandVal starlark.Value
 	var triggers starlark.Value
+	var forEach bool
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"cmd", &commandVal,
-		"trigger?", &triggers); err != nil {
+		"trigger?", &triggers,
+		"for_each?", &forEach); err != nil {
 		return nil, err
 	}
 
@@ -186,8 +257,28 @@ func (s *tiltfileState) liveUpdateRun(thread *starlark.Thread, fn *starlark.Buil
 	}
 
 	ret := liveUpdateRunStep{
-		command:  command,
-		triggers: triggerStrings,
+		command:     command,
+		triggers:    triggerStrings,
+		forEachFile: forEach,
+		position:    thread.CallFrame(1).Pos,
+	}
+	s.recordLiveUpdateStep(ret)
+	return ret, nil
+}
+
+func (s *tiltfileState) liveUpdateSignalProcess(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	signal := "SIGHUP"
+	if err := s.unpackArgs(fn.Name(), args, kwargs, "signal?", &signal); err != nil {
+		return nil, err
+	}
+
+	normalized, err := model.NormalizeSignal(signal)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+
+	ret := liveUpdateSignalProcessStep{
+		signal:   normalized,
 		position: thread.CallFrame(1).Pos,
 	}
 	s.recordLiveUpdateStep(ret)
@@ -210,14 +301,21 @@ func (s *tiltfileState) liveUpdateStepToModel(t *starlark.Thread, l liveUpdateSt
 	switch x := l.(type) {
 	case liveUpdateFallBackOnStep:
 		return model.LiveUpdateFallBackOnStep{Files: x.files}, nil
+	case liveUpdateLocalPreSyncStep:
+		return model.LiveUpdateLocalPreSyncStep{Command: x.command}, nil
 	case liveUpdateSyncStep:
-		// NOTE(maia): we assume a Linux container, and so use `path` to check that
-		// the sync dest is a LINUX abs path! (`filepath.IsAbs` varies depending on
-		// OS the binary was installed for; `path` deals with Linux paths only.)
-		if !path.IsAbs(x.remotePath) {
+		// NOTE(maia): we assume a Linux container unless the dest starts with a drive
+		// letter, and so use `path` to check that the sync dest is a LINUX abs path!
+		// (`filepath.IsAbs` varies depending on OS the binary was installed for;
+		// `path` deals with Linux paths only.)
+		if !path.IsAbs(x.remotePath) && !model.IsWindowsContainerPath(x.remotePath) {
 			return nil, fmt.Errorf("sync destination '%s' (%s) is not absolute", x.remotePath, x.position.String())
 		}
-		return model.LiveUpdateSyncStep{Source: x.localPath, Dest: x.remotePath}, nil
+		return model.LiveUpdateSyncStep{
+			Source:    x.localPath,
+			Dest:      model.NormalizeContainerPath(x.remotePath),
+			MustExist: x.mustExist,
+		}, nil
 	case liveUpdateRunStep:
 		return model.LiveUpdateRunStep{
 			Command: x.command,
@@ -225,9 +323,12 @@ func (s *tiltfileState) liveUpdateStepToModel(t *starlark.Thread, l liveUpdateSt
 				Paths:         x.triggers,
 				BaseDirectory: starkit.AbsWorkingDir(t),
 			},
+			ForEachFile: x.forEachFile,
 		}, nil
 	case liveUpdateRestartContainerStep:
 		return model.LiveUpdateRestartContainerStep{}, nil
+	case liveUpdateSignalProcessStep:
+		return model.LiveUpdateSignalProcessStep{Signal: x.signal}, nil
 	default:
 		return nil, fmt.Errorf("internal error - unknown liveUpdateStep '%v' of type '%T', declared at %s", l, l, l.declarationPos())
 	}
@@ -255,6 +356,39 @@ func (s *tiltfileState) liveUpdateFromSteps(t *starlark.Thread, maybeSteps starl
 	return model.NewLiveUpdate(modelSteps, starkit.AbsWorkingDir(t))
 }
 
+// Sync sources that aren't under a watched path never get "file changed"
+// events, so they're nonsensical input.
+//
+// Sync sources that don't exist usually mean the Tiltfile refers to a path
+// that was renamed or deleted, which otherwise goes unnoticed until a file
+// changes. They might also be created later (e.g., by a build), so we only
+// warn, unless the step opts in to failing with `must_exist`.
+func (s *tiltfileState) validateSyncSources(lu model.LiveUpdate, watchedPaths []string) error {
+	for _, step := range lu.Steps {
+		sync, ok := step.(model.LiveUpdateSyncStep)
+		if !ok {
+			continue
+		}
+
+		if !ospath.IsChildOfOne(watchedPaths, sync.Source) {
+			return fmt.Errorf("sync step source '%s' is not a child of any watched filepaths (%v)",
+				sync.Source, watchedPaths)
+		}
+
+		_, err := os.Stat(sync.Source)
+		if !os.IsNotExist(err) {
+			continue
+		}
+
+		if sync.MustExist {
+			return fmt.Errorf("sync step source '%s' does not exist", sync.Source)
+		}
+		s.logger.Warnf("sync step source '%s' does not exist. "+
+			"If it won't be created later, check that it wasn't renamed", sync.Source)
+	}
+	return nil
+}
+
 func (s *tiltfileState) consumeLiveUpdateStep(stepToConsume liveUpdateStep) {
 	delete(s.unconsumedLiveUpdateSteps, stepToConsume.declarationPos())
 }
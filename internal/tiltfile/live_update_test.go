@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -49,6 +51,225 @@ docker_build('gcr.io/foo', 'foo',
 	f.loadErrString("sync destination", "'baz'", "is not absolute")
 }
 
+func TestLiveUpdateSyncWindowsDest(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.MkdirAll("a")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.file("imageA.dockerfile", `FROM mcr.microsoft.com/windows/servercore`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/image-a', 'a', dockerfile='imageA.dockerfile',
+             live_update=[
+               sync('a', 'C:\\app'),
+             ])
+k8s_yaml('foo.yaml')
+`)
+	f.load()
+
+	lu := model.LiveUpdate{
+		Steps: []model.LiveUpdateStep{
+			model.LiveUpdateSyncStep{Source: f.JoinPath("a"), Dest: "C:/app"},
+		},
+		BaseDir: f.Path(),
+	}
+	f.assertNextManifest("foo",
+		db(image("gcr.io/image-a"), lu))
+}
+
+func TestLiveUpdateContainer(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.MkdirAll("a")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.file("imageA.dockerfile", `FROM golang:1.10`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/image-a', 'a', dockerfile='imageA.dockerfile',
+             live_update=[sync('a', '/app')],
+             live_update_container='app')
+k8s_yaml('foo.yaml')
+`)
+	f.load()
+
+	lu := model.LiveUpdate{
+		Steps: []model.LiveUpdateStep{
+			model.LiveUpdateSyncStep{Source: f.JoinPath("a"), Dest: "/app"},
+		},
+		BaseDir:       f.Path(),
+		ContainerName: "app",
+	}
+	f.assertNextManifest("foo",
+		db(image("gcr.io/image-a"), lu))
+}
+
+func TestLiveUpdateContainerCustomBuild(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.MkdirAll("a")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.file("Tiltfile", `
+custom_build('gcr.io/image-a', 'docker build -t $TAG a', ['a'],
+             live_update=[sync('a', '/app')],
+             live_update_container='app')
+k8s_yaml('foo.yaml')
+`)
+	f.load()
+
+	lu := model.LiveUpdate{
+		Steps: []model.LiveUpdateStep{
+			model.LiveUpdateSyncStep{Source: f.JoinPath("a"), Dest: "/app"},
+		},
+		BaseDir:       f.Path(),
+		ContainerName: "app",
+	}
+	f.assertNextManifest("foo",
+		cb(image("gcr.io/image-a"), lu))
+}
+
+func TestLiveUpdateLocalPreSync(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.MkdirAll("a")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.file("imageA.dockerfile", `FROM golang:1.10`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/image-a', 'a', dockerfile='imageA.dockerfile',
+             live_update=[
+               local_pre_sync('make assets'),
+               sync('a', '/app'),
+             ])
+k8s_yaml('foo.yaml')
+`)
+	f.load()
+
+	lu := model.LiveUpdate{
+		Steps: []model.LiveUpdateStep{
+			model.LiveUpdateLocalPreSyncStep{Command: model.ToHostCmdInDir("make assets", f.Path())},
+			model.LiveUpdateSyncStep{Source: f.JoinPath("a"), Dest: "/app"},
+		},
+		BaseDir: f.Path(),
+	}
+	f.assertNextManifest("foo",
+		db(image("gcr.io/image-a"), lu))
+}
+
+func TestLiveUpdateRunForEach(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.MkdirAll("a")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.file("imageA.dockerfile", `FROM golang:1.10`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/image-a', 'a', dockerfile='imageA.dockerfile',
+             live_update=[
+               sync('a', '/app'),
+               run('protoc $CHANGED_FILES', trigger=['a/protos'], for_each=True),
+             ])
+k8s_yaml('foo.yaml')
+`)
+	f.load()
+
+	lu := model.LiveUpdate{
+		Steps: []model.LiveUpdateStep{
+			model.LiveUpdateSyncStep{Source: f.JoinPath("a"), Dest: "/app"},
+			model.LiveUpdateRunStep{
+				Command:     model.ToUnixCmdInDir("protoc $CHANGED_FILES", f.Path()),
+				Triggers:    model.NewPathSet([]string{"a/protos"}, f.Path()),
+				ForEachFile: true,
+			},
+		},
+		BaseDir: f.Path(),
+	}
+	f.assertNextManifest("foo",
+		db(image("gcr.io/image-a"), lu))
+}
+
+func TestLiveUpdateSignalProcess(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.MkdirAll("a")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.file("imageA.dockerfile", `FROM golang:1.10`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/image-a', 'a', dockerfile='imageA.dockerfile',
+             live_update=[
+               sync('a', '/app'),
+               signal_process(),
+             ])
+k8s_yaml('foo.yaml')
+`)
+	f.load()
+
+	lu := model.LiveUpdate{
+		Steps: []model.LiveUpdateStep{
+			model.LiveUpdateSyncStep{Source: f.JoinPath("a"), Dest: "/app"},
+			model.LiveUpdateSignalProcessStep{Signal: "HUP"},
+		},
+		BaseDir: f.Path(),
+	}
+	f.assertNextManifest("foo",
+		db(image("gcr.io/image-a"), lu))
+}
+
+func TestLiveUpdateSignalProcessCustomSignal(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.MkdirAll("a")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.file("imageA.dockerfile", `FROM golang:1.10`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/image-a', 'a', dockerfile='imageA.dockerfile',
+             live_update=[
+               sync('a', '/app'),
+               signal_process(signal='sigusr2'),
+             ])
+k8s_yaml('foo.yaml')
+`)
+	f.load()
+
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, "USR2", m.ImageTargetAt(0).LiveUpdateInfo().SignalOnUpdate())
+}
+
+func TestLiveUpdateSignalProcessInvalidSignal(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    signal_process(signal='SIGBOGUS'),
+  ]
+)`)
+	f.loadErrString("signal_process", "BOGUS")
+}
+
+func TestLiveUpdateLocalPreSyncAfterSync(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo', '/baz'),
+    local_pre_sync('make assets'),
+  ]
+)`)
+	f.loadErrString("live_update", "all local_pre_sync steps must precede all sync and run steps")
+}
+
 func TestLiveUpdateRunBeforeSync(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -117,6 +338,47 @@ docker_build('gcr.io/foo', 'foo',
 	f.loadErrString("run", "triggers", "'bar'", "contained value '4' of type 'int'. it may only contain strings")
 }
 
+func TestLiveUpdateUpdater(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.MkdirAll("a")
+	f.MkdirAll("b")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.yaml("bar.yaml", deployment("bar", image("gcr.io/image-b")))
+	f.file("imageA.dockerfile", `FROM golang:1.10`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/image-a', 'a', dockerfile='imageA.dockerfile',
+             live_update=[sync('a', '/app')],
+             live_update_updater='docker')
+custom_build('gcr.io/image-b', 'docker build -t $TAG b', ['b'],
+             live_update=[sync('b', '/app')],
+             live_update_updater='exec')
+k8s_yaml(['foo.yaml', 'bar.yaml'])
+`)
+	f.load()
+
+	foo := f.assertNextManifest("foo")
+	assert.Equal(t, model.LiveUpdateUpdaterDocker, foo.ImageTargetAt(0).LiveUpdateInfo().Updater)
+	bar := f.assertNextManifest("bar")
+	assert.Equal(t, model.LiveUpdateUpdaterExec, bar.ImageTargetAt(0).LiveUpdateInfo().Updater)
+}
+
+func TestLiveUpdateUpdaterInvalid(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.file("a/Dockerfile", `FROM golang:1.10`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/image-a', 'a',
+             live_update=[sync('a', '/app')],
+             live_update_updater='ssh')
+k8s_yaml('foo.yaml')
+`)
+	f.loadErrString("live_update_updater", `unknown live update updater "ssh"`)
+}
+
 func TestLiveUpdateDockerBuildUnqualifiedImageName(t *testing.T) {
 	f := newLiveUpdateFixture(t)
 	defer f.TearDown()
@@ -261,6 +523,114 @@ k8s_yaml('foo.yaml')
 	}
 }
 
+func TestLiveUpdateFallBackOnGlobs(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.MkdirAll("a")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/image-a")))
+	f.file("imageA.dockerfile", `FROM golang:1.10`)
+	f.file("Tiltfile", `
+docker_build('gcr.io/image-a', 'a', dockerfile='imageA.dockerfile',
+             live_update=[
+               fall_back_on(['a/**/*.go', '!a/vendor']),
+               sync('a', '/app'),
+             ])
+k8s_yaml('foo.yaml')
+`)
+	f.load()
+
+	lu := model.LiveUpdate{
+		Steps: []model.LiveUpdateStep{
+			model.LiveUpdateFallBackOnStep{
+				Files: []string{f.JoinPath("a/**/*.go"), "!" + f.JoinPath("a/vendor")},
+			},
+			model.LiveUpdateSyncStep{Source: f.JoinPath("a"), Dest: "/app"},
+		},
+		BaseDir: f.Path(),
+	}
+	f.assertNextManifest("foo",
+		db(image("gcr.io/image-a"), lu))
+}
+
+func TestLiveUpdateSyncSourceMissing(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo/generated', '/baz'),
+  ]
+)`)
+	f.loadAllowWarnings()
+	f.assertWarnings(fmt.Sprintf("sync step source '%s' does not exist. "+
+		"If it won't be created later, check that it wasn't renamed", f.JoinPath("foo", "generated")))
+
+	lu := model.LiveUpdate{
+		Steps: []model.LiveUpdateStep{
+			model.LiveUpdateSyncStep{Source: f.JoinPath("foo", "generated"), Dest: "/baz"},
+		},
+		BaseDir: f.Path(),
+	}
+	f.assertNextManifest("foo", db(image("gcr.io/foo"), lu))
+}
+
+func TestLiveUpdateSyncSourceMissingWarnsOncePerImage(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.dockerfile("foo/Dockerfile")
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo")), deployment("foo2", image("gcr.io/foo")))
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo/generated', '/baz'),
+  ]
+)`)
+	f.loadAllowWarnings()
+	f.assertWarnings(fmt.Sprintf("sync step source '%s' does not exist. "+
+		"If it won't be created later, check that it wasn't renamed", f.JoinPath("foo", "generated")))
+}
+
+func TestLiveUpdateSyncSourceMustExist(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo/renamed', '/baz', must_exist=True),
+  ]
+)`)
+	f.loadErrString(fmt.Sprintf("sync step source '%s' does not exist", f.JoinPath("foo", "renamed")))
+}
+
+func TestLiveUpdateSyncSourceNotWatched(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+
+	f.setupFoo()
+	f.MkdirAll("bar")
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('bar', '/baz'),
+  ]
+)`)
+	f.loadErrString(fmt.Sprintf("sync step source '%s' is not a child of any watched filepaths", f.JoinPath("bar")))
+}
+
 func TestLiveUpdateFallBackTriggersOutsideOfDockerBuildContext(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -403,6 +773,7 @@ type liveUpdateFixture struct {
 
 func (f *liveUpdateFixture) init() {
 	f.dockerfile("foo/Dockerfile")
+	f.MkdirAll("foo/b")
 	f.yaml("foo.yaml", deployment("foo", image(f.configuredImageName)))
 
 	luSteps := `[
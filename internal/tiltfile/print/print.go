@@ -2,10 +2,12 @@ package print
 
 import (
 	"errors"
+	"fmt"
 
 	"go.starlark.net/starlark"
 
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
@@ -25,20 +27,82 @@ func (Extension) OnStart(env *starkit.Environment) error {
 	if err != nil {
 		return err
 	}
+	err = env.AddBuiltin("debug", debug)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
+// An error from fail() that asks Tilt to exit with a particular code.
+type ExitCodeError struct {
+	msg  string
+	code int
+}
+
+func NewExitCodeError(msg string, code int) ExitCodeError {
+	return ExitCodeError{msg: msg, code: code}
+}
+
+func (e ExitCodeError) Error() string {
+	return e.msg
+}
+
+func (e ExitCodeError) ExitCode() int {
+	return e.code
+}
+
+// Returns the exit code requested by fail(), if any.
+func ExitCode(err error) (int, bool) {
+	var exitErr ExitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code, true
+	}
+	return 0, false
+}
+
 func fail(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var msg string
-	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "msg", &msg)
+	code := -1
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "msg", &msg, "code?", &code)
 	if err != nil {
 		return nil, err
 	}
 
-	return nil, errors.New(msg)
+	if code == -1 {
+		return nil, errors.New(msg)
+	}
+	if code < 1 || code > 255 {
+		return nil, fmt.Errorf("%s: code must be between 1 and 255 (got: %d)", fn.Name(), code)
+	}
+
+	return nil, NewExitCodeError(msg, code)
 }
 
 func warn(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var msg string
+	var fields value.StringStringMap
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "msg", &msg, "fields?", &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := starkit.ContextFromThread(thread)
+	if err != nil {
+		return nil, err
+	}
+
+	l := logger.Get(ctx)
+	if len(fields) > 0 {
+		l = l.WithFields(logger.Fields(fields.AsMap()))
+	}
+	l.Warnf("%s", msg)
+
+	return starlark.None, nil
+}
+
+// Logs at debug level, so it only shows up when Tilt runs with --debug.
+func debug(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var msg string
 	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs, "msg", &msg)
 	if err != nil {
@@ -50,7 +114,7 @@ func warn(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kw
 		return nil, err
 	}
 
-	logger.Get(ctx).Warnf("%s", msg)
+	logger.Get(ctx).Debugf("%s", msg)
 
 	return starlark.None, nil
 }
@@ -25,6 +25,62 @@ func TestWarn(t *testing.T) {
 	assert.Equal(t, "problem 1\n", out.String())
 }
 
+func TestWarnWithFields(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	out := bytes.NewBuffer(nil)
+	var observedFields logger.Fields
+	log := logger.NewFuncLogger(false, logger.WarnLvl, func(level logger.Level, fields logger.Fields, b []byte) error {
+		observedFields = fields
+		_, err := out.Write(b)
+		return err
+	})
+	ctx := logger.WithLogger(context.Background(), log)
+	f.SetContext(ctx)
+
+	f.File("Tiltfile", "warn('problem 1', fields={'service': 'api'})")
+	_, err := f.ExecFile("Tiltfile")
+	assert.NoError(t, err)
+	assert.Equal(t, "problem 1\n", out.String())
+	assert.Equal(t, logger.Fields{"service": "api"}, observedFields)
+}
+
+func TestWarnWithInvalidFields(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	f.SetContext(logger.WithLogger(context.Background(), logger.NewLogger(logger.WarnLvl, bytes.NewBuffer(nil))))
+
+	f.File("Tiltfile", "warn('problem 1', fields={'service': 1})")
+	_, err := f.ExecFile("Tiltfile")
+	assert.Error(t, err)
+}
+
+func TestDebug(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	out := bytes.NewBuffer(nil)
+	log := logger.NewLogger(logger.DebugLvl, out)
+	f.SetContext(logger.WithLogger(context.Background(), log))
+
+	f.File("Tiltfile", "debug('details 1')")
+	_, err := f.ExecFile("Tiltfile")
+	assert.NoError(t, err)
+	assert.Equal(t, "details 1\n", out.String())
+}
+
+func TestDebugHiddenByDefault(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	out := bytes.NewBuffer(nil)
+	log := logger.NewLogger(logger.InfoLvl, out)
+	f.SetContext(logger.WithLogger(context.Background(), log))
+
+	f.File("Tiltfile", "debug('details 1')")
+	_, err := f.ExecFile("Tiltfile")
+	assert.NoError(t, err)
+	assert.Equal(t, "", out.String())
+}
+
 func TestFail(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -35,6 +91,39 @@ func TestFail(t *testing.T) {
 	}
 }
 
+func TestFailWithoutCode(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	f.File("Tiltfile", "fail('problem 1')")
+	_, err := f.ExecFile("Tiltfile")
+	_, ok := ExitCode(err)
+	assert.False(t, ok)
+}
+
+func TestFailWithCode(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	f.File("Tiltfile", "fail('config invalid', code=2)")
+	_, err := f.ExecFile("Tiltfile")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "config invalid")
+	}
+
+	code, ok := ExitCode(starkit.UnpackBacktrace(err))
+	assert.True(t, ok)
+	assert.Equal(t, 2, code)
+}
+
+func TestFailWithInvalidCode(t *testing.T) {
+	f := newFixture(t)
+	defer f.TearDown()
+	f.File("Tiltfile", "fail('config invalid', code=256)")
+	_, err := f.ExecFile("Tiltfile")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "code must be between 1 and 255 (got: 256)")
+	}
+}
+
 func newFixture(tb testing.TB) *starkit.Fixture {
 	return starkit.NewFixture(tb, NewExtension())
 }
@@ -1,7 +1,6 @@
 package starkit
 
 import (
-	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
 )
 
@@ -24,11 +23,26 @@ func UnpackBacktrace(err error) error {
 	}
 
 	if bestEvalError != nil {
-		return errors.New(bestEvalError.Backtrace())
+		return backtraceError{backtrace: bestEvalError.Backtrace(), cause: err}
 	}
 	return err
 }
 
+// An error that prints as a Starlark backtrace, but still unwraps
+// to the original error so that callers can inspect it.
+type backtraceError struct {
+	backtrace string
+	cause     error
+}
+
+func (e backtraceError) Error() string {
+	return e.backtrace
+}
+
+func (e backtraceError) Unwrap() error {
+	return e.cause
+}
+
 // go 1.13 error wrapper
 type wrapper interface {
 	Unwrap() error
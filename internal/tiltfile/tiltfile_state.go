@@ -334,9 +334,11 @@ const (
 
 	// live update functions
 	fallBackOnN       = "fall_back_on"
+	localPreSyncN     = "local_pre_sync"
 	syncN             = "sync"
 	runN              = "run"
 	restartContainerN = "restart_container"
+	signalProcessN    = "signal_process"
 
 	// trigger mode
 	triggerModeN       = "trigger_mode"
@@ -508,9 +510,11 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{helmN, s.helm},
 		{triggerModeN, s.triggerModeFn},
 		{fallBackOnN, s.liveUpdateFallBackOn},
+		{localPreSyncN, s.liveUpdateLocalPreSync},
 		{syncN, s.liveUpdateSync},
 		{runN, s.liveUpdateRun},
 		{restartContainerN, s.liveUpdateRestartContainer},
+		{signalProcessN, s.liveUpdateSignalProcess},
 		{enableFeatureN, s.enableFeature},
 		{disableFeatureN, s.disableFeature},
 		{disableSnapshotsN, s.disableSnapshots},
@@ -1084,8 +1088,11 @@ func (s *tiltfileState) defaultedPortForwards(pfs []model.PortForward) []model.P
 }
 
 func (s *tiltfileState) validateLiveUpdatesForManifests(manifests []model.Manifest) error {
+	// An image can be deployed by several manifests, but we only want to
+	// check (and warn about) its live update once.
+	validated := make(map[model.TargetID]bool)
 	for _, m := range manifests {
-		err := s.validateLiveUpdatesForManifest(m)
+		err := s.validateLiveUpdatesForManifest(m, validated)
 		if err != nil {
 			return err
 		}
@@ -1095,7 +1102,7 @@ func (s *tiltfileState) validateLiveUpdatesForManifests(manifests []model.Manife
 
 // validateLiveUpdatesForManifest checks any image targets on the
 // given manifest the contain any illegal LiveUpdates
-func (s *tiltfileState) validateLiveUpdatesForManifest(m model.Manifest) error {
+func (s *tiltfileState) validateLiveUpdatesForManifest(m model.Manifest, validated map[model.TargetID]bool) error {
 	g, err := model.NewTargetGraph(m.TargetSpecs())
 	if err != nil {
 		return err
@@ -1111,9 +1118,10 @@ func (s *tiltfileState) validateLiveUpdatesForManifest(m model.Manifest) error {
 
 		// TODO(nick): If an undeployed base image has a live-update component, we
 		// should probably emit a different kind of warning.
-		if !isDeployed {
+		if !isDeployed || validated[iTarg.ID()] {
 			continue
 		}
+		validated[iTarg.ID()] = true
 
 		err = s.validateLiveUpdate(iTarg, g)
 		if err != nil {
@@ -1143,16 +1151,15 @@ func (s *tiltfileState) validateLiveUpdate(iTarget model.ImageTarget, g model.Ta
 		return err
 	}
 
-	// Verify that all a) sync step src's and b) fall_back_on files are children of a watched paths.
-	// (If not, we'll never even get "file changed" events for them--they're nonsensical input, throw an error.)
-	for _, sync := range lu.SyncSteps() {
-		if !ospath.IsChildOfOne(watchedPaths, sync.LocalPath) {
-			return fmt.Errorf("sync step source '%s' is not a child of any watched filepaths (%v)",
-				sync.LocalPath, watchedPaths)
-		}
+	err = s.validateSyncSources(lu, watchedPaths)
+	if err != nil {
+		return err
 	}
 
+	// Verify that all fall_back_on files are children of a watched paths.
+	// (If not, we'll never even get "file changed" events for them--they're nonsensical input, throw an error.)
 	for _, path := range lu.FallBackOnFiles().Paths {
+		path = strings.TrimPrefix(path, "!")
 		if !filepath.IsAbs(path) {
 			return fmt.Errorf("internal error: path not resolved correctly! Please report to https://github.com/tilt-dev/tilt/issues : %s", path)
 		}
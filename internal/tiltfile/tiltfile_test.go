@@ -3227,7 +3227,11 @@ docker_build('gcr.io/some-project-162817/sancho-sidecar', './sidecar',
 		t.Fatal(err)
 	}
 
-	f.load()
+	// Neither sync source exists yet, which is OK.
+	f.loadAllowWarnings()
+	f.assertWarnings(
+		fmt.Sprintf("sync step source '%s' does not exist. If it won't be created later, check that it wasn't renamed", f.JoinPath("sancho/foo")),
+		fmt.Sprintf("sync step source '%s' does not exist. If it won't be created later, check that it wasn't renamed", f.JoinPath("sidecar/baz")))
 	f.assertNextManifest("sancho",
 		db(image("gcr.io/some-project-162817/sancho"), expectedLU1),
 		db(image("gcr.io/some-project-162817/sancho-sidecar"), expectedLU2),
@@ -4813,6 +4817,213 @@ func TestK8sUpsertTimeout(t *testing.T) {
 	}
 }
 
+func TestLiveUpdateLimits(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		tiltfile            string
+		expectErrorContains string
+		expectedMaxFiles    int
+		expectedMaxBytes    int64
+	}{
+		{
+			name:     "unlimited if func not called",
+			tiltfile: "print('hello world')",
+		},
+		{
+			name:             "set limits",
+			tiltfile:         "update_settings(live_update_max_files=100, live_update_max_bytes=1000000)",
+			expectedMaxFiles: 100,
+			expectedMaxBytes: 1000000,
+		},
+		{
+			name:     "zero means unlimited",
+			tiltfile: "update_settings(live_update_max_files=0, live_update_max_bytes=0)",
+		},
+		{
+			name:                "NaN error",
+			tiltfile:            "update_settings(live_update_max_files='boop')",
+			expectErrorContains: "got starlark.String, want int",
+		},
+		{
+			name:                "must be non-negative",
+			tiltfile:            "update_settings(live_update_max_bytes=-1)",
+			expectErrorContains: "must be >= 0",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.TearDown()
+
+			f.file("Tiltfile", tc.tiltfile)
+
+			if tc.expectErrorContains != "" {
+				f.loadErrString(tc.expectErrorContains)
+				return
+			}
+
+			f.load()
+			assert.Equal(t, tc.expectedMaxFiles, f.loadResult.UpdateSettings.LiveUpdateMaxFiles())
+			assert.Equal(t, tc.expectedMaxBytes, f.loadResult.UpdateSettings.LiveUpdateMaxBytes())
+		})
+	}
+}
+
+func TestLiveUpdateSkipRuns(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		tiltfile            string
+		expectErrorContains string
+		expectedSkipRuns    bool
+	}{
+		{
+			name:     "runs by default",
+			tiltfile: "print('hello world')",
+		},
+		{
+			name:             "skip runs",
+			tiltfile:         "update_settings(live_update_skip_runs=True)",
+			expectedSkipRuns: true,
+		},
+		{
+			name:                "not a bool",
+			tiltfile:            "update_settings(live_update_skip_runs='yes')",
+			expectErrorContains: "got starlark.String, want bool",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.TearDown()
+
+			f.file("Tiltfile", tc.tiltfile)
+
+			if tc.expectErrorContains != "" {
+				f.loadErrString(tc.expectErrorContains)
+				return
+			}
+
+			f.load()
+			assert.Equal(t, tc.expectedSkipRuns, f.loadResult.UpdateSettings.LiveUpdateSkipRuns())
+		})
+	}
+}
+
+func TestLiveUpdateStrictSyncs(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		tiltfile            string
+		expectErrorContains string
+		expectedStrict      bool
+	}{
+		{
+			name:     "warns by default",
+			tiltfile: "print('hello world')",
+		},
+		{
+			name:           "strict syncs",
+			tiltfile:       "update_settings(live_update_strict_syncs=True)",
+			expectedStrict: true,
+		},
+		{
+			name:                "not a bool",
+			tiltfile:            "update_settings(live_update_strict_syncs=1)",
+			expectErrorContains: "got starlark.Int, want bool",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.TearDown()
+
+			f.file("Tiltfile", tc.tiltfile)
+
+			if tc.expectErrorContains != "" {
+				f.loadErrString(tc.expectErrorContains)
+				return
+			}
+
+			f.load()
+			assert.Equal(t, tc.expectedStrict, f.loadResult.UpdateSettings.LiveUpdateStrictSyncs())
+		})
+	}
+}
+
+func TestLiveUpdateCompressMinBytes(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		tiltfile            string
+		expectErrorContains string
+		expectedMinBytes    int64
+	}{
+		{
+			name:     "never compress by default",
+			tiltfile: "print('hello world')",
+		},
+		{
+			name:             "compress min bytes",
+			tiltfile:         "update_settings(live_update_compress_min_bytes=1048576)",
+			expectedMinBytes: 1048576,
+		},
+		{
+			name:                "negative",
+			tiltfile:            "update_settings(live_update_compress_min_bytes=-1)",
+			expectErrorContains: "min live update bytes to compress must be >= 0 (got: -1)",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.TearDown()
+
+			f.file("Tiltfile", tc.tiltfile)
+
+			if tc.expectErrorContains != "" {
+				f.loadErrString(tc.expectErrorContains)
+				return
+			}
+
+			f.load()
+			assert.Equal(t, tc.expectedMinBytes, f.loadResult.UpdateSettings.LiveUpdateCompressMinBytes())
+		})
+	}
+}
+
+func TestLiveUpdateToleratePartialFailure(t *testing.T) {
+	for _, tc := range []struct {
+		name                string
+		tiltfile            string
+		expectErrorContains string
+		expectedTolerate    bool
+	}{
+		{
+			name:     "falls back by default",
+			tiltfile: "print('hello world')",
+		},
+		{
+			name:             "tolerate partial failure",
+			tiltfile:         "update_settings(live_update_tolerate_partial_failure=True)",
+			expectedTolerate: true,
+		},
+		{
+			name:                "not a bool",
+			tiltfile:            "update_settings(live_update_tolerate_partial_failure='yes')",
+			expectErrorContains: "got starlark.String, want bool",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.TearDown()
+
+			f.file("Tiltfile", tc.tiltfile)
+
+			if tc.expectErrorContains != "" {
+				f.loadErrString(tc.expectErrorContains)
+				return
+			}
+
+			f.load()
+			assert.Equal(t, tc.expectedTolerate, f.loadResult.UpdateSettings.LiveUpdateToleratePartialFailure())
+		})
+	}
+}
+
 func TestUpdateSettingsCalledTwice(t *testing.T) {
 	f := newFixture(t)
 	defer f.TearDown()
@@ -28,10 +28,16 @@ func (e Extension) OnStart(env *starkit.Environment) error {
 }
 
 func (e *Extension) updateSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-	var maxParallelUpdates, k8sUpsertTimeoutSecs starlark.Value
+	var maxParallelUpdates, k8sUpsertTimeoutSecs, liveUpdateMaxFiles, liveUpdateMaxBytes, liveUpdateSkipRuns, liveUpdateStrictSyncs, liveUpdateCompressMinBytes, liveUpdateToleratePartialFailure starlark.Value
 	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
 		"max_parallel_updates?", &maxParallelUpdates,
-		"k8s_upsert_timeout_secs?", &k8sUpsertTimeoutSecs); err != nil {
+		"k8s_upsert_timeout_secs?", &k8sUpsertTimeoutSecs,
+		"live_update_max_files?", &liveUpdateMaxFiles,
+		"live_update_max_bytes?", &liveUpdateMaxBytes,
+		"live_update_skip_runs?", &liveUpdateSkipRuns,
+		"live_update_strict_syncs?", &liveUpdateStrictSyncs,
+		"live_update_compress_min_bytes?", &liveUpdateCompressMinBytes,
+		"live_update_tolerate_partial_failure?", &liveUpdateToleratePartialFailure); err != nil {
 		return nil, err
 	}
 
@@ -53,6 +59,45 @@ func (e *Extension) updateSettings(thread *starlark.Thread, fn *starlark.Builtin
 			k8sUpsertTimeoutSecs)
 	}
 
+	lumf, lumfPassed, err := valueToInt(liveUpdateMaxFiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "update_settings: for parameter \"live_update_max_files\"")
+	}
+	if lumfPassed && lumf < 0 {
+		return nil, fmt.Errorf("max number of live update files must be >= 0 (got: %d)", lumf)
+	}
+
+	lumb, lumbPassed, err := valueToInt64(liveUpdateMaxBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "update_settings: for parameter \"live_update_max_bytes\"")
+	}
+	if lumbPassed && lumb < 0 {
+		return nil, fmt.Errorf("max number of live update bytes must be >= 0 (got: %d)", lumb)
+	}
+
+	lusr, lusrPassed, err := valueToBool(liveUpdateSkipRuns)
+	if err != nil {
+		return nil, errors.Wrap(err, "update_settings: for parameter \"live_update_skip_runs\"")
+	}
+
+	luss, lussPassed, err := valueToBool(liveUpdateStrictSyncs)
+	if err != nil {
+		return nil, errors.Wrap(err, "update_settings: for parameter \"live_update_strict_syncs\"")
+	}
+
+	lucmb, lucmbPassed, err := valueToInt64(liveUpdateCompressMinBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "update_settings: for parameter \"live_update_compress_min_bytes\"")
+	}
+	if lucmbPassed && lucmb < 0 {
+		return nil, fmt.Errorf("min live update bytes to compress must be >= 0 (got: %d)", lucmb)
+	}
+
+	lutpf, lutpfPassed, err := valueToBool(liveUpdateToleratePartialFailure)
+	if err != nil {
+		return nil, errors.Wrap(err, "update_settings: for parameter \"live_update_tolerate_partial_failure\"")
+	}
+
 	err = starkit.SetState(thread, func(settings model.UpdateSettings) model.UpdateSettings {
 		if mpuPassed {
 			settings = settings.WithMaxParallelUpdates(mpu)
@@ -60,6 +105,24 @@ func (e *Extension) updateSettings(thread *starlark.Thread, fn *starlark.Builtin
 		if kutsPassed {
 			settings = settings.WithK8sUpsertTimeout(time.Duration(kuts) * time.Second)
 		}
+		if lumfPassed {
+			settings = settings.WithLiveUpdateMaxFiles(lumf)
+		}
+		if lumbPassed {
+			settings = settings.WithLiveUpdateMaxBytes(lumb)
+		}
+		if lusrPassed {
+			settings = settings.WithLiveUpdateSkipRuns(lusr)
+		}
+		if lussPassed {
+			settings = settings.WithLiveUpdateStrictSyncs(luss)
+		}
+		if lucmbPassed {
+			settings = settings.WithLiveUpdateCompressMinBytes(lucmb)
+		}
+		if lutpfPassed {
+			settings = settings.WithLiveUpdateToleratePartialFailure(lutpf)
+		}
 		return settings
 	})
 
@@ -78,6 +141,32 @@ func valueToInt(v starlark.Value) (val int, wasPassed bool, err error) {
 	}
 }
 
+func valueToInt64(v starlark.Value) (val int64, wasPassed bool, err error) {
+	switch x := v.(type) {
+	case nil, starlark.NoneType:
+		return 0, false, nil
+	case starlark.Int:
+		val, ok := x.Int64()
+		if !ok {
+			return 0, true, fmt.Errorf("value out of range for int64: %s", x.String())
+		}
+		return val, true, nil
+	default:
+		return 0, true, fmt.Errorf("got %T, want int", x)
+	}
+}
+
+func valueToBool(v starlark.Value) (val bool, wasPassed bool, err error) {
+	switch x := v.(type) {
+	case nil, starlark.NoneType:
+		return false, false, nil
+	case starlark.Bool:
+		return bool(x), true, nil
+	default:
+		return false, true, fmt.Errorf("got %T, want bool", x)
+	}
+}
+
 var _ starkit.StatefulExtension = Extension{}
 
 func MustState(model starkit.Model) model.UpdateSettings {
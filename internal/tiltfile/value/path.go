@@ -2,8 +2,11 @@ package value
 
 import (
 	"fmt"
+	"strings"
 
 	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 )
 
 type LocalPath struct {
@@ -30,6 +33,9 @@ func (p *LocalPath) Unpack(v starlark.Value) error {
 type LocalPathList struct {
 	t     *starlark.Thread
 	Value []string
+
+	// If true, strings starting with `!` are exclusion patterns, and keep the `!`
+	allowExclusions bool
 }
 
 func NewLocalPathListUnpacker(t *starlark.Thread) LocalPathList {
@@ -38,10 +44,30 @@ func NewLocalPathListUnpacker(t *starlark.Thread) LocalPathList {
 	}
 }
 
+// Like NewLocalPathListUnpacker, but for path patterns that may be
+// exclusions (e.g., "!foo/bar"). Exclusions keep their `!` in front of the
+// absolute path.
+func NewLocalPatternListUnpacker(t *starlark.Thread) LocalPathList {
+	return LocalPathList{
+		t:               t,
+		allowExclusions: true,
+	}
+}
+
+func (p *LocalPathList) toAbsPath(v starlark.Value) (string, error) {
+	if p.allowExclusions {
+		str, ok := starlark.AsString(v)
+		if ok && strings.HasPrefix(str, "!") {
+			return "!" + starkit.AbsPath(p.t, strings.TrimPrefix(str, "!")), nil
+		}
+	}
+	return ValueToAbsPath(p.t, v)
+}
+
 func (p *LocalPathList) Unpack(v starlark.Value) error {
 	_, ok := AsString(v)
 	if ok {
-		str, err := ValueToAbsPath(p.t, v)
+		str, err := p.toAbsPath(v)
 		if err != nil {
 			return err
 		}
@@ -65,7 +91,7 @@ func (p *LocalPathList) Unpack(v starlark.Value) error {
 	values := []string{}
 	var item starlark.Value
 	for iter.Next(&item) {
-		str, err := ValueToAbsPath(p.t, item)
+		str, err := p.toAbsPath(item)
 		if err != nil {
 			return fmt.Errorf("unpacking list item at index %d: %v", len(values), err)
 		}
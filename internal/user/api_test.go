@@ -1,9 +1,15 @@
 package user
 
 import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"runtime"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tilt-dev/wmclient/pkg/dirs"
 
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
@@ -27,3 +33,69 @@ func TestWriteMode(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, model.MetricsLocal, prefs.MetricsMode)
 }
+
+func TestUpdateCreatesMissingDir(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	dir := dirs.NewTiltDevDirAt(f.JoinPath("missing", ".tilt-dev"))
+	up := NewFilePrefs(dir)
+	err := UpdateMetricsMode(up, model.MetricsLocal)
+	require.NoError(t, err)
+
+	prefs, err := up.Get()
+	require.NoError(t, err)
+	assert.Equal(t, model.MetricsLocal, prefs.MetricsMode)
+}
+
+func TestFailedWriteLeavesPriorPrefs(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	dir := dirs.NewTiltDevDirAt(f.Path())
+	up := NewFilePrefs(dir)
+	err := UpdateMetricsMode(up, model.MetricsLocal)
+	require.NoError(t, err)
+
+	err = writeFileAtomic(dir, userPrefsFileName, func(w io.Writer) error {
+		_, _ = w.Write([]byte("metricsMo"))
+		return fmt.Errorf("disk full")
+	})
+	require.EqualError(t, err, "disk full")
+
+	prefs, err := up.Get()
+	require.NoError(t, err)
+	assert.Equal(t, model.MetricsLocal, prefs.MetricsMode)
+
+	// The partially-written temp file is cleaned up.
+	entries, err := ioutil.ReadDir(f.Path())
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, userPrefsFileName, entries[0].Name())
+}
+
+func TestUpdateKeepsFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("windows doesn't have unix file modes")
+	}
+
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	dir := dirs.NewTiltDevDirAt(f.Path())
+	up := NewFilePrefs(dir)
+	err := UpdateMetricsMode(up, model.MetricsLocal)
+	require.NoError(t, err)
+
+	info, err := os.Stat(f.JoinPath(userPrefsFileName))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	require.NoError(t, os.Chmod(f.JoinPath(userPrefsFileName), 0640))
+	err = UpdateMetricsMode(up, model.MetricsDefault)
+	require.NoError(t, err)
+
+	info, err = os.Stat(f.JoinPath(userPrefsFileName))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+}
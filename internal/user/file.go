@@ -2,7 +2,10 @@ package user
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/tilt-dev/wmclient/pkg/dirs"
 	"gopkg.in/yaml.v3"
@@ -41,9 +44,60 @@ func (f *filePrefs) Update(newPrefs Prefs) error {
 	if err != nil {
 		return fmt.Errorf("update user prefs: %v", err)
 	}
-	err = f.dir.WriteFile(userPrefsFileName, string(contents))
+	err = writeFileAtomic(f.dir, userPrefsFileName, func(w io.Writer) error {
+		_, err := w.Write(contents)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("update user prefs: %v", err)
 	}
 	return nil
 }
+
+// Writes to a temp file in the same directory, syncs it, then renames it into
+// place, so that a crash or failed write never leaves a truncated file behind.
+//
+// Keeps the permissions of the file we're replacing (or 0644 for a new file),
+// since temp files are only readable by their owner.
+func writeFileAtomic(dir *dirs.TiltDevDir, p string, write func(w io.Writer) error) error {
+	err := dir.MkdirAll(filepath.Dir(p))
+	if err != nil {
+		return err
+	}
+
+	abs, err := dir.Abs(p)
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(abs); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(abs), filepath.Base(abs)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// After a successful rename, the temp file no longer exists.
+		_ = os.Remove(tmp.Name())
+	}()
+
+	err = tmp.Chmod(mode)
+	if err == nil {
+		err = write(tmp)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	closeErr := tmp.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	return os.Rename(tmp.Name(), abs)
+}
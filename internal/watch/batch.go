@@ -0,0 +1,140 @@
+package watch
+
+import (
+	"sync"
+	"time"
+)
+
+// How long we wait after the first event in a batch for more to arrive.
+const defaultBatchWindow = 10 * time.Millisecond
+
+// The most events we'll put in one batch, so that a huge checkout
+// doesn't hold everything back until it's done.
+const defaultMaxBatchSize = 1000
+
+// A Notify that wraps another Notify, and can hand its events out in batches.
+//
+// Events() still sends the inner watcher's events one at a time. Batching
+// only starts once someone asks for BatchedEvents(), and the two read off
+// the same events, so a caller should only use one of them.
+type batchNotify struct {
+	inner   Notify
+	window  time.Duration
+	maxSize int
+
+	batches       chan []FileEvent
+	stop          chan struct{}
+	startBatching sync.Once
+	closeOnce     sync.Once
+}
+
+func newBatchNotify(inner Notify, window time.Duration, maxSize int) *batchNotify {
+	return &batchNotify{
+		inner:   inner,
+		window:  window,
+		maxSize: maxSize,
+		batches: make(chan []FileEvent),
+		stop:    make(chan struct{}),
+	}
+}
+
+func (b *batchNotify) Start() error {
+	return b.inner.Start()
+}
+
+func (b *batchNotify) Close() error {
+	var err error
+	b.closeOnce.Do(func() {
+		close(b.stop)
+		err = b.inner.Close()
+	})
+	return err
+}
+
+func (b *batchNotify) Events() chan FileEvent {
+	return b.inner.Events()
+}
+
+func (b *batchNotify) BatchedEvents() <-chan []FileEvent {
+	b.startBatching.Do(func() {
+		go b.loop()
+	})
+	return b.batches
+}
+
+func (b *batchNotify) Errors() chan error {
+	return b.inner.Errors()
+}
+
+func (b *batchNotify) WatchedPaths() []string {
+	if diag, ok := b.inner.(WatchDiagnostics); ok {
+		return diag.WatchedPaths()
+	}
+	return nil
+}
+
+func (b *batchNotify) WatchCount() int64 {
+	if diag, ok := b.inner.(WatchDiagnostics); ok {
+		return diag.WatchCount()
+	}
+	return 0
+}
+
+func (b *batchNotify) SetIgnore(ignore PathMatcher) {
+	if setter, ok := b.inner.(IgnoreSetter); ok {
+		setter.SetIgnore(ignore)
+	}
+}
+
+func (b *batchNotify) loop() {
+	defer close(b.batches)
+
+	events := b.inner.Events()
+	for {
+		var batch []FileEvent
+		select {
+		case <-b.stop:
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			batch = append(batch, e)
+		}
+
+		// The window starts at the first event, so a steady stream of
+		// events can't delay a batch by more than one window.
+		timeout := time.After(b.window)
+		closed := false
+	collect:
+		for len(batch) < b.maxSize {
+			select {
+			case <-b.stop:
+				return
+			case e, ok := <-events:
+				if !ok {
+					closed = true
+					break collect
+				}
+				batch = append(batch, e)
+			case <-timeout:
+				break collect
+			}
+		}
+
+		select {
+		case <-b.stop:
+			return
+		case b.batches <- batch:
+		}
+
+		if closed {
+			return
+		}
+	}
+}
+
+var _ Notify = &batchNotify{}
+var _ BatchNotify = &batchNotify{}
+var _ WatchDiagnostics = &batchNotify{}
+var _ IgnoreSetter = &batchNotify{}
@@ -0,0 +1,93 @@
+package watch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchGroupsEventsInWindow(t *testing.T) {
+	inner := newFakeNotify()
+	b := newBatchNotify(inner, 50*time.Millisecond, 100)
+	require.NoError(t, b.Start())
+	defer b.Close()
+
+	batches := b.BatchedEvents()
+	inner.events <- NewFileEvent("/src/a.txt")
+	inner.events <- NewFileEvent("/src/b.txt")
+	inner.events <- NewFileEvent("/src/a.txt")
+
+	assert.Equal(t, []string{"/src/a.txt", "/src/b.txt", "/src/a.txt"}, readBatchPaths(t, batches))
+}
+
+func TestBatchMaxSize(t *testing.T) {
+	inner := newFakeNotify()
+	b := newBatchNotify(inner, time.Hour, 2)
+	require.NoError(t, b.Start())
+	defer b.Close()
+
+	batches := b.BatchedEvents()
+	go func() {
+		inner.events <- NewFileEvent("/src/a.txt")
+		inner.events <- NewFileEvent("/src/b.txt")
+		inner.events <- NewFileEvent("/src/c.txt")
+	}()
+
+	assert.Equal(t, []string{"/src/a.txt", "/src/b.txt"}, readBatchPaths(t, batches))
+}
+
+func TestBatchFlushesOnClose(t *testing.T) {
+	inner := newFakeNotify()
+	b := newBatchNotify(inner, time.Hour, 100)
+	require.NoError(t, b.Start())
+	defer b.Close()
+
+	batches := b.BatchedEvents()
+	inner.events <- NewFileEvent("/src/a.txt")
+	close(inner.events)
+
+	assert.Equal(t, []string{"/src/a.txt"}, readBatchPaths(t, batches))
+	_, ok := <-batches
+	assert.False(t, ok, "batches should be closed")
+}
+
+func TestBatchEventsStayUnbatched(t *testing.T) {
+	inner := newFakeNotify()
+	b := newBatchNotify(inner, time.Hour, 100)
+	require.NoError(t, b.Start())
+	defer b.Close()
+
+	go func() {
+		inner.events <- NewFileEvent("/src/a.txt")
+	}()
+
+	select {
+	case e := <-b.Events():
+		assert.Equal(t, "/src/a.txt", e.Path())
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+}
+
+func TestBatchCloseTwice(t *testing.T) {
+	b := newBatchNotify(newFakeNotify(), time.Hour, 100)
+	require.NoError(t, b.Start())
+	require.NoError(t, b.Close())
+	require.NoError(t, b.Close())
+}
+
+func readBatchPaths(t *testing.T, batches <-chan []FileEvent) []string {
+	select {
+	case batch := <-batches:
+		var paths []string
+		for _, e := range batch {
+			paths = append(paths, e.Path())
+		}
+		return paths
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for batch")
+		return nil
+	}
+}
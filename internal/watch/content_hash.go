@@ -0,0 +1,112 @@
+package watch
+
+import (
+	"container/list"
+	"hash/fnv"
+	"io"
+	"os"
+
+	"github.com/tilt-dev/tilt/internal/ospath"
+)
+
+// The most files we'll remember checksums for.
+const maxContentHashes = 10000
+
+// Files bigger than this are always reported; hashing them on every write
+// would cost more than the no-op update we're trying to avoid.
+const maxHashedFileSize = 10 * 1024 * 1024
+
+// Remembers a checksum of the files we've seen change, so that we can skip
+// events for writes that didn't change anything (e.g., editors that re-save
+// files when they lose focus).
+//
+// When it's full, we forget the file that changed least recently.
+type contentHashCache struct {
+	maxSize int
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type contentHashEntry struct {
+	path string
+	hash uint64
+}
+
+func newContentHashCache(maxSize int) *contentHashCache {
+	return &contentHashCache{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Records the file's current checksum.
+//
+// Returns false only if the file has the same content as the last time we
+// saw it. If we can't hash the file (e.g., it's gone or it's too big),
+// we assume it changed.
+func (c *contentHashCache) update(path string) bool {
+	hash, ok := hashFile(path)
+	if !ok {
+		c.remove(path)
+		return true
+	}
+
+	if el, ok := c.entries[path]; ok {
+		c.lru.MoveToFront(el)
+		entry := el.Value.(*contentHashEntry)
+		if entry.hash == hash {
+			return false
+		}
+		entry.hash = hash
+		return true
+	}
+
+	c.entries[path] = c.lru.PushFront(&contentHashEntry{path: path, hash: hash})
+	if c.lru.Len() > c.maxSize {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*contentHashEntry).path)
+	}
+	return true
+}
+
+// Forget the path, and everything under it if it's a directory.
+func (c *contentHashCache) remove(path string) {
+	if el, ok := c.entries[path]; ok {
+		c.lru.Remove(el)
+		delete(c.entries, path)
+		return
+	}
+
+	for p, el := range c.entries {
+		if ospath.IsChild(path, p) {
+			c.lru.Remove(el)
+			delete(c.entries, p)
+		}
+	}
+}
+
+func (c *contentHashCache) len() int {
+	return c.lru.Len()
+}
+
+func hashFile(path string) (uint64, bool) {
+	fi, err := os.Lstat(path)
+	if err != nil || !fi.Mode().IsRegular() || fi.Size() > maxHashedFileSize {
+		return 0, false
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer func() { _ = file.Close() }()
+
+	h := fnv.New64a()
+	_, err = io.Copy(h, file)
+	if err != nil {
+		return 0, false
+	}
+	return h.Sum64(), true
+}
@@ -0,0 +1,61 @@
+package watch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+func TestContentHashUnchanged(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	c := newContentHashCache(10)
+	path := f.WriteFile("a.txt", "hello")
+	assert.True(t, c.update(path), "first sighting")
+	assert.False(t, c.update(path), "same content")
+
+	f.WriteFile("a.txt", "goodbye")
+	assert.True(t, c.update(path), "new content")
+}
+
+func TestContentHashEvictsOldest(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	c := newContentHashCache(2)
+	a := f.WriteFile("a.txt", "a")
+	b := f.WriteFile("b.txt", "b")
+	d := f.WriteFile("d.txt", "d")
+	c.update(a)
+	c.update(b)
+	c.update(a)
+	c.update(d)
+
+	assert.Equal(t, 2, c.len())
+	assert.False(t, c.update(a), "a was used recently")
+	assert.True(t, c.update(b), "b should have been evicted")
+}
+
+func TestContentHashRemovesDeletedFiles(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	c := newContentHashCache(10)
+	a := f.WriteFile("dir/a.txt", "a")
+	b := f.WriteFile("dir/b.txt", "b")
+	other := f.WriteFile("other.txt", "other")
+	c.update(a)
+	c.update(b)
+	c.update(other)
+
+	f.Rm("dir")
+	c.remove(f.JoinPath("dir"))
+	assert.Equal(t, 1, c.len())
+
+	f.Rm("other.txt")
+	assert.True(t, c.update(other), "missing files count as changed")
+	assert.Equal(t, 0, c.len())
+}
@@ -0,0 +1,202 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/ospath"
+)
+
+// Set TILT_WATCH_DEBOUNCE to a Go duration (e.g., "50ms") to buffer file events
+// for that long and drop duplicate events for the same path.
+//
+// Editors that save with write-then-rename (or that write the same file several
+// times per save) otherwise send several events for one logical change.
+const DebounceEnvVar = "TILT_WATCH_DEBOUNCE"
+
+// Returns 0 (i.e., no debouncing) unless the env var is set to a positive duration.
+func DesiredDebounceWindow() time.Duration {
+	envVar := os.Getenv(DebounceEnvVar)
+	if envVar != "" {
+		window, err := time.ParseDuration(envVar)
+		if err == nil && window > 0 {
+			return window
+		}
+	}
+	return 0
+}
+
+// Set TILT_WATCH_COALESCE_DIRS=1 to report changes per watched path instead of
+// per file: each debounce window sends at most one event for each watched path
+// with changes under it. This keeps bulk operations (e.g., a codegen step that
+// rewrites hundreds of files) down to a single event.
+//
+// Only applies to watchers made with NewDirWatcher. Image builds and live
+// updates need to know which files changed, so they never coalesce.
+//
+// If TILT_WATCH_DEBOUNCE isn't set, we use a short default window.
+const CoalesceDirsEnvVar = "TILT_WATCH_COALESCE_DIRS"
+
+const defaultCoalesceWindow = 100 * time.Millisecond
+
+func ShouldCoalesceDirs() bool {
+	coalesce, err := strconv.ParseBool(os.Getenv(CoalesceDirsEnvVar))
+	return err == nil && coalesce
+}
+
+// Returns a function that maps a file to the closest of the given watched
+// paths that contains it. Files outside all of them map to themselves.
+func nearestWatchedPath(paths []string) func(file string) string {
+	var roots []string
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err == nil {
+			roots = append(roots, abs)
+		}
+	}
+
+	return func(file string) string {
+		nearest := ""
+		for _, root := range roots {
+			if len(root) > len(nearest) && ospath.IsChild(root, file) {
+				nearest = root
+			}
+		}
+		if nearest == "" {
+			return file
+		}
+		return nearest
+	}
+}
+
+// A Notify that wraps another Notify, buffering its events for a short window
+// and de-duplicating them by path.
+//
+// The window starts at the first event in a batch, so a steady stream of
+// writes can't delay an event by more than one window.
+type debounceNotify struct {
+	inner  Notify
+	window time.Duration
+	events chan FileEvent
+	stop   chan struct{}
+
+	closeOnce sync.Once
+
+	// When non-nil, maps each event's path to the path we report (e.g., its
+	// watched directory) before de-duplicating.
+	coalesce func(path string) string
+}
+
+func newDebounceNotify(inner Notify, window time.Duration) *debounceNotify {
+	return &debounceNotify{
+		inner:  inner,
+		window: window,
+		events: make(chan FileEvent),
+		stop:   make(chan struct{}),
+	}
+}
+
+func (d *debounceNotify) Start() error {
+	err := d.inner.Start()
+	if err != nil {
+		return err
+	}
+	go d.loop()
+	return nil
+}
+
+func (d *debounceNotify) Close() error {
+	var err error
+	d.closeOnce.Do(func() {
+		close(d.stop)
+		err = d.inner.Close()
+	})
+	return err
+}
+
+func (d *debounceNotify) Events() chan FileEvent {
+	return d.events
+}
+
+func (d *debounceNotify) Errors() chan error {
+	return d.inner.Errors()
+}
+
+func (d *debounceNotify) WatchedPaths() []string {
+	if diag, ok := d.inner.(WatchDiagnostics); ok {
+		return diag.WatchedPaths()
+	}
+	return nil
+}
+
+func (d *debounceNotify) WatchCount() int64 {
+	if diag, ok := d.inner.(WatchDiagnostics); ok {
+		return diag.WatchCount()
+	}
+	return 0
+}
+
+func (d *debounceNotify) SetIgnore(ignore PathMatcher) {
+	if setter, ok := d.inner.(IgnoreSetter); ok {
+		setter.SetIgnore(ignore)
+	}
+}
+
+func (d *debounceNotify) loop() {
+	defer close(d.events)
+
+	var pending []FileEvent
+	seen := make(map[string]bool)
+	var flush <-chan time.Time
+
+	for {
+		select {
+		case <-d.stop:
+			return
+
+		case e, ok := <-d.inner.Events():
+			if !ok {
+				d.send(pending)
+				return
+			}
+
+			if d.coalesce != nil {
+				e = NewFileEvent(d.coalesce(e.Path()))
+			}
+			if !seen[e.Path()] {
+				seen[e.Path()] = true
+				pending = append(pending, e)
+			}
+			if flush == nil {
+				flush = time.After(d.window)
+			}
+
+		case <-flush:
+			if !d.send(pending) {
+				return
+			}
+			pending = nil
+			seen = make(map[string]bool)
+			flush = nil
+		}
+	}
+}
+
+// Returns false if the watcher was closed before we could send everything.
+func (d *debounceNotify) send(events []FileEvent) bool {
+	for _, e := range events {
+		select {
+		case <-d.stop:
+			return false
+		case d.events <- e:
+		}
+	}
+	return true
+}
+
+var _ Notify = &debounceNotify{}
+var _ WatchDiagnostics = &debounceNotify{}
+var _ IgnoreSetter = &debounceNotify{}
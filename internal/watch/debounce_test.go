@@ -0,0 +1,199 @@
+package watch
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestDebounceWindowEnvVar(t *testing.T) {
+	orig := os.Getenv(DebounceEnvVar)
+	defer os.Setenv(DebounceEnvVar, orig)
+
+	os.Setenv(DebounceEnvVar, "")
+	assert.Equal(t, time.Duration(0), DesiredDebounceWindow())
+
+	os.Setenv(DebounceEnvVar, "a")
+	assert.Equal(t, time.Duration(0), DesiredDebounceWindow())
+
+	os.Setenv(DebounceEnvVar, "50ms")
+	assert.Equal(t, 50*time.Millisecond, DesiredDebounceWindow())
+}
+
+func TestDebounceDedupesByPath(t *testing.T) {
+	inner := newFakeNotify()
+	d := newDebounceNotify(inner, 50*time.Millisecond)
+	require.NoError(t, d.Start())
+	defer d.Close()
+
+	for i := 0; i < 5; i++ {
+		inner.events <- NewFileEvent("/src/a.txt")
+	}
+	inner.events <- NewFileEvent("/src/b.txt")
+	inner.events <- NewFileEvent("/src/a.txt")
+
+	assert.Equal(t, []string{"/src/a.txt", "/src/b.txt"}, readDebouncedPaths(d, 200*time.Millisecond))
+}
+
+func TestDebounceFlushesEachWindow(t *testing.T) {
+	inner := newFakeNotify()
+	d := newDebounceNotify(inner, 20*time.Millisecond)
+	require.NoError(t, d.Start())
+	defer d.Close()
+
+	inner.events <- NewFileEvent("/src/a.txt")
+	assert.Equal(t, []string{"/src/a.txt"}, readDebouncedPaths(d, 100*time.Millisecond))
+
+	// A change after the window has been flushed is a new event.
+	inner.events <- NewFileEvent("/src/a.txt")
+	assert.Equal(t, []string{"/src/a.txt"}, readDebouncedPaths(d, 100*time.Millisecond))
+}
+
+func TestDebounceFlushesOnClose(t *testing.T) {
+	inner := newFakeNotify()
+	d := newDebounceNotify(inner, time.Hour)
+	require.NoError(t, d.Start())
+
+	inner.events <- NewFileEvent("/src/a.txt")
+	close(inner.events)
+
+	var paths []string
+	for e := range d.Events() {
+		paths = append(paths, e.Path())
+	}
+	assert.Equal(t, []string{"/src/a.txt"}, paths)
+}
+
+func TestDebounceRealWatcher(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+	defer numberOfWatches.Set(0)
+
+	inner, err := newWatcher([]string{f.Path()}, EmptyMatcher{}, logger.NewTestLogger(bytes.NewBuffer(nil)))
+	require.NoError(t, err)
+	d := newDebounceNotify(inner, 200*time.Millisecond)
+	require.NoError(t, d.Start())
+	defer d.Close()
+
+	path := f.JoinPath("a.txt")
+	for i := 0; i < 5; i++ {
+		f.WriteFile("a.txt", time.Now().String())
+	}
+
+	assert.Equal(t, []string{path}, readDebouncedPaths(d, 500*time.Millisecond))
+}
+
+func TestDebounceCloseTwice(t *testing.T) {
+	d := newDebounceNotify(newFakeNotify(), time.Hour)
+	require.NoError(t, d.Start())
+	require.NoError(t, d.Close())
+	require.NoError(t, d.Close())
+}
+
+func TestCoalesceDirsEnvVar(t *testing.T) {
+	orig := os.Getenv(CoalesceDirsEnvVar)
+	defer os.Setenv(CoalesceDirsEnvVar, orig)
+
+	os.Setenv(CoalesceDirsEnvVar, "")
+	assert.False(t, ShouldCoalesceDirs())
+
+	os.Setenv(CoalesceDirsEnvVar, "1")
+	assert.True(t, ShouldCoalesceDirs())
+}
+
+func TestNearestWatchedPath(t *testing.T) {
+	nearest := nearestWatchedPath([]string{"/src", "/src/web", "/lib/a.txt"})
+	assert.Equal(t, "/src", nearest("/src/api/main.go"))
+	assert.Equal(t, "/src/web", nearest("/src/web/index.js"))
+	assert.Equal(t, "/src/web", nearest("/src/web"))
+	assert.Equal(t, "/lib/a.txt", nearest("/lib/a.txt"))
+	assert.Equal(t, "/other/b.txt", nearest("/other/b.txt"))
+}
+
+func TestCoalesceDirsRealWatcher(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+	defer numberOfWatches.Set(0)
+
+	dir := f.JoinPath("gen")
+	f.MkdirAll("gen")
+
+	inner, err := newWatcher([]string{dir}, EmptyMatcher{}, logger.NewTestLogger(bytes.NewBuffer(nil)))
+	require.NoError(t, err)
+	d := newDebounceNotify(inner, 200*time.Millisecond)
+	d.coalesce = nearestWatchedPath([]string{dir})
+	require.NoError(t, d.Start())
+	defer d.Close()
+
+	for i := 0; i < 100; i++ {
+		f.WriteFile(filepath.Join("gen", fmt.Sprintf("file%d.txt", i)), "hello")
+	}
+
+	assert.Equal(t, []string{dir}, readDebouncedPaths(d, 500*time.Millisecond))
+}
+
+func readDebouncedPaths(d *debounceNotify, timeout time.Duration) []string {
+	var paths []string
+	deadline := time.After(timeout)
+	for {
+		select {
+		case e := <-d.Events():
+			paths = append(paths, e.Path())
+		case <-deadline:
+			return paths
+		}
+	}
+}
+
+type fakeNotify struct {
+	events chan FileEvent
+	errors chan error
+}
+
+func newFakeNotify() *fakeNotify {
+	return &fakeNotify{
+		events: make(chan FileEvent),
+		errors: make(chan error),
+	}
+}
+
+func (n *fakeNotify) Start() error           { return nil }
+func (n *fakeNotify) Close() error           { return nil }
+func (n *fakeNotify) Events() chan FileEvent { return n.events }
+func (n *fakeNotify) Errors() chan error     { return n.errors }
+
+var _ Notify = &fakeNotify{}
+
+func TestNewWatcherDoesNotCoalesceDirs(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+	defer numberOfWatches.Set(0)
+
+	orig := os.Getenv(CoalesceDirsEnvVar)
+	defer os.Setenv(CoalesceDirsEnvVar, orig)
+	os.Setenv(CoalesceDirsEnvVar, "1")
+
+	dir := f.JoinPath("src")
+	f.MkdirAll("src")
+
+	notify, err := NewWatcher([]string{dir}, EmptyMatcher{}, logger.NewTestLogger(bytes.NewBuffer(nil)))
+	require.NoError(t, err)
+	_, isDebounce := notify.(*batchNotify).inner.(*debounceNotify)
+	assert.False(t, isDebounce)
+
+	notify, err = NewDirWatcher([]string{dir}, EmptyMatcher{}, logger.NewTestLogger(bytes.NewBuffer(nil)))
+	require.NoError(t, err)
+	d, isDebounce := notify.(*batchNotify).inner.(*debounceNotify)
+	if assert.True(t, isDebounce) {
+		assert.NotNil(t, d.coalesce)
+	}
+}
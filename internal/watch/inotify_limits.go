@@ -0,0 +1,88 @@
+// +build !darwin
+
+package watch
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const inotifyProcDir = "/proc/sys/fs/inotify"
+
+const (
+	inotifyMaxUserInstances = "max_user_instances"
+	inotifyMaxUserWatches   = "max_user_watches"
+)
+
+// The smallest values we'll suggest, even if the current limits are tiny.
+var minRecommendedInotifyLimits = map[string]int{
+	inotifyMaxUserInstances: 1024,
+	inotifyMaxUserWatches:   524288,
+}
+
+// Reads an inotify limit (e.g., max_user_watches) from procDir.
+//
+// Returns 0 if the limit can't be read.
+func readInotifyLimit(procDir string, name string) int {
+	contents, err := ioutil.ReadFile(filepath.Join(procDir, name))
+	if err != nil {
+		return 0
+	}
+	limit, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return 0
+	}
+	return limit
+}
+
+// Suggests a new value for an inotify limit: at least double the current limit
+// and double what we need, rounded up to a power of two.
+func recommendedInotifyLimit(name string, current int, needed int) int {
+	target := current * 2
+	if needed*2 > target {
+		target = needed * 2
+	}
+
+	result := minRecommendedInotifyLimits[name]
+	for result < target {
+		result *= 2
+	}
+	return result
+}
+
+func formatInotifyLimit(limit int) string {
+	if limit == 0 {
+		return "unknown"
+	}
+	return strconv.Itoa(limit)
+}
+
+// Creates an error explaining which inotify limit we hit, what the current
+// limits are, and what to raise the exhausted one to.
+//
+// `needed` is our best guess of how many of `exhausted` we were trying to use,
+// or 0 if we don't know.
+func inotifyLimitError(procDir string, exhausted string, needed int) error {
+	instances := readInotifyLimit(procDir, inotifyMaxUserInstances)
+	watches := readInotifyLimit(procDir, inotifyMaxUserWatches)
+
+	current := instances
+	if exhausted == inotifyMaxUserWatches {
+		current = watches
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Hit OS limits watching files (fs.inotify.%s).\n", exhausted))
+	sb.WriteString(fmt.Sprintf("Current limits: fs.inotify.%s=%s, fs.inotify.%s=%s\n",
+		inotifyMaxUserInstances, formatInotifyLimit(instances),
+		inotifyMaxUserWatches, formatInotifyLimit(watches)))
+	if exhausted == inotifyMaxUserWatches && needed > 0 {
+		sb.WriteString(fmt.Sprintf("Tilt is trying to watch %d directories.\n", needed))
+	}
+	sb.WriteString(fmt.Sprintf("To raise the limit, run 'sudo sysctl fs.inotify.%s=%d'",
+		exhausted, recommendedInotifyLimit(exhausted, current, needed)))
+	return fmt.Errorf("%s", sb.String())
+}
@@ -0,0 +1,56 @@
+// +build !darwin
+
+package watch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+func TestInotifyLimitErrorWatches(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	f.WriteFile(inotifyMaxUserInstances, "128\n")
+	f.WriteFile(inotifyMaxUserWatches, "8192\n")
+
+	err := inotifyLimitError(f.Path(), inotifyMaxUserWatches, 600000)
+	assert.Equal(t, "Hit OS limits watching files (fs.inotify.max_user_watches).\n"+
+		"Current limits: fs.inotify.max_user_instances=128, fs.inotify.max_user_watches=8192\n"+
+		"Tilt is trying to watch 600000 directories.\n"+
+		"To raise the limit, run 'sudo sysctl fs.inotify.max_user_watches=2097152'", err.Error())
+}
+
+func TestInotifyLimitErrorInstances(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	f.WriteFile(inotifyMaxUserInstances, "128\n")
+	f.WriteFile(inotifyMaxUserWatches, "8192\n")
+
+	err := inotifyLimitError(f.Path(), inotifyMaxUserInstances, 0)
+	assert.Equal(t, "Hit OS limits watching files (fs.inotify.max_user_instances).\n"+
+		"Current limits: fs.inotify.max_user_instances=128, fs.inotify.max_user_watches=8192\n"+
+		"To raise the limit, run 'sudo sysctl fs.inotify.max_user_instances=1024'", err.Error())
+}
+
+func TestInotifyLimitErrorUnreadable(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	f.WriteFile(inotifyMaxUserWatches, "garbage")
+
+	err := inotifyLimitError(f.Path(), inotifyMaxUserWatches, 10)
+	assert.Contains(t, err.Error(), "fs.inotify.max_user_instances=unknown, fs.inotify.max_user_watches=unknown")
+	assert.Contains(t, err.Error(), "'sudo sysctl fs.inotify.max_user_watches=524288'")
+}
+
+func TestRecommendedInotifyLimit(t *testing.T) {
+	assert.Equal(t, 524288, recommendedInotifyLimit(inotifyMaxUserWatches, 8192, 0))
+	assert.Equal(t, 1048576, recommendedInotifyLimit(inotifyMaxUserWatches, 524288, 1000))
+	assert.Equal(t, 2097152, recommendedInotifyLimit(inotifyMaxUserWatches, 8192, 600000))
+	assert.Equal(t, 2048, recommendedInotifyLimit(inotifyMaxUserInstances, 1024, 0))
+}
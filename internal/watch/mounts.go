@@ -0,0 +1,156 @@
+package watch
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tilt-dev/tilt/internal/ospath"
+)
+
+// On Linux, describes every mount the process can see.
+const mountInfoPath = "/proc/self/mountinfo"
+
+// Filesystems where changes made outside this kernel (e.g., on the host of a
+// VM or on a file server) don't generate inotify events.
+var pollOnlyFSTypes = map[string]bool{
+	"9p":         true,
+	"cifs":       true,
+	"fuse.sshfs": true,
+	"nfs":        true,
+	"nfs4":       true,
+	"smb3":       true,
+	"vboxsf":     true,
+}
+
+type mountInfo struct {
+	mountPoint string
+	fsType     string
+	readOnly   bool
+}
+
+// Whether OS file events are unreliable for files under this mount.
+//
+// A read-only mount can only change underneath us (e.g., a read-only bind
+// mount of a directory that something else writes to), and those changes often
+// don't make it to our inotify watches.
+func (m mountInfo) needsPolling() bool {
+	return m.readOnly || pollOnlyFSTypes[m.fsType]
+}
+
+// Reads the mounts from a mountinfo file (see proc(5)).
+//
+// Returns nil if the file can't be read (e.g., we're not on Linux).
+func readMountInfo(path string) []mountInfo {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = f.Close() }()
+
+	var result []mountInfo
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m, ok := parseMountInfoLine(scanner.Text())
+		if ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// A mountinfo line looks like:
+//
+// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+//
+// where the fifth field is the mount point, the sixth is the mount options,
+// and the fs type comes after the optional fields and a "-" separator.
+func parseMountInfoLine(line string) (mountInfo, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 6 {
+		return mountInfo{}, false
+	}
+
+	fsType := ""
+	for i := 6; i < len(fields)-1; i++ {
+		if fields[i] == "-" {
+			fsType = fields[i+1]
+			break
+		}
+	}
+
+	readOnly := false
+	for _, opt := range strings.Split(fields[5], ",") {
+		if opt == "ro" {
+			readOnly = true
+			break
+		}
+	}
+
+	return mountInfo{
+		mountPoint: unescapeMountPath(fields[4]),
+		fsType:     fsType,
+		readOnly:   readOnly,
+	}, true
+}
+
+// The kernel escapes spaces, tabs, newlines, and backslashes in mount paths
+// as octal (e.g., "\040" for a space).
+func unescapeMountPath(p string) string {
+	if !strings.Contains(p, `\`) {
+		return p
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(p); i++ {
+		if p[i] == '\\' && i+4 <= len(p) {
+			c, err := strconv.ParseUint(p[i+1:i+4], 8, 8)
+			if err == nil {
+				sb.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		sb.WriteByte(p[i])
+	}
+	return sb.String()
+}
+
+// Finds the mount that a path lives on: the one with the longest mount point
+// that contains it. Later mounts at the same mount point shadow earlier ones.
+func findMount(mounts []mountInfo, path string) (mountInfo, bool) {
+	var result mountInfo
+	found := false
+	for _, m := range mounts {
+		if path != m.mountPoint && !ospath.IsChild(m.mountPoint, path) {
+			continue
+		}
+		if !found || len(m.mountPoint) >= len(result.mountPoint) {
+			result = m
+			found = true
+		}
+	}
+	return result, found
+}
+
+// Splits the paths to watch into the ones that need the polling watcher,
+// and the ones that OS file events work for.
+func splitPathsByWatchStrategy(mounts []mountInfo, paths []string) (poll []string, native []string) {
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			native = append(native, p)
+			continue
+		}
+
+		m, ok := findMount(mounts, abs)
+		if ok && m.needsPolling() {
+			poll = append(poll, p)
+		} else {
+			native = append(native, p)
+		}
+	}
+	return poll, native
+}
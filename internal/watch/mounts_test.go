@@ -0,0 +1,124 @@
+// +build !windows
+
+package watch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+const testMountInfo = `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+40 22 8:1 /home/nick/src /src ro,relatime shared:1 - ext4 /dev/sda1 rw
+41 22 0:50 / /mnt/nfs rw,relatime shared:30 - nfs4 server:/export rw,vers=4.2
+42 22 0:51 / /mnt/my\040share rw,relatime - cifs //server/share rw
+43 22 0:52 / /src/cache rw,relatime - tmpfs tmpfs rw
+`
+
+func TestReadMountInfo(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	path := f.WriteFile("mountinfo", testMountInfo)
+	assert.Equal(t, []mountInfo{
+		{mountPoint: "/", fsType: "ext4"},
+		{mountPoint: "/src", fsType: "ext4", readOnly: true},
+		{mountPoint: "/mnt/nfs", fsType: "nfs4"},
+		{mountPoint: "/mnt/my share", fsType: "cifs"},
+		{mountPoint: "/src/cache", fsType: "tmpfs"},
+	}, readMountInfo(path))
+}
+
+func TestReadMountInfoMissing(t *testing.T) {
+	assert.Nil(t, readMountInfo(filepath.Join(t.TempDir(), "mountinfo")))
+}
+
+func TestSplitPathsByWatchStrategy(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	mounts := readMountInfo(f.WriteFile("mountinfo", testMountInfo))
+	poll, native := splitPathsByWatchStrategy(mounts, []string{
+		"/home/nick/src",
+		"/src/app",
+		"/src/cache/build",
+		"/mnt/nfs/data",
+		"/mnt/my share/docs",
+		"/srcfoo",
+	})
+	assert.Equal(t, []string{"/src/app", "/mnt/nfs/data", "/mnt/my share/docs"}, poll)
+	assert.Equal(t, []string{"/home/nick/src", "/src/cache/build", "/srcfoo"}, native)
+}
+
+func TestSplitPathsByWatchStrategyNoMounts(t *testing.T) {
+	poll, native := splitPathsByWatchStrategy(nil, []string{"/src/app"})
+	assert.Empty(t, poll)
+	assert.Equal(t, []string{"/src/app"}, native)
+}
+
+func TestWatcherPerMountMixed(t *testing.T) {
+	orig := os.Getenv(PollIntervalEnvVar)
+	defer os.Setenv(PollIntervalEnvVar, orig)
+	os.Setenv(PollIntervalEnvVar, "50ms")
+
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	nativeDir := f.JoinPath("native")
+	pollDir := f.JoinPath("poll")
+	f.MkdirAll("native")
+	f.MkdirAll("poll")
+
+	mounts := []mountInfo{
+		{mountPoint: "/", fsType: "ext4"},
+		{mountPoint: pollDir, fsType: "ext4", readOnly: true},
+	}
+	out := bytes.NewBuffer(nil)
+	l := logger.NewLogger(logger.InfoLvl, out)
+	notify, err := newWatcherPerMount(mounts, []string{nativeDir, pollDir}, EmptyMatcher{}, l)
+	require.NoError(t, err)
+	require.IsType(t, &multiNotify{}, notify)
+	assert.Contains(t, out.String(), pollDir)
+	assert.NotContains(t, out.String(), nativeDir)
+
+	require.NoError(t, notify.Start())
+	defer func() { _ = notify.Close() }()
+
+	nativeFile := f.WriteFile("native/a.txt", "hello")
+	pollFile := f.WriteFile("poll/b.txt", "hello")
+
+	seen := map[string]bool{}
+	timeout := time.After(5 * time.Second)
+	for !seen[nativeFile] || !seen[pollFile] {
+		select {
+		case e := <-notify.Events():
+			seen[e.Path()] = true
+		case err := <-notify.Errors():
+			t.Fatal(err)
+		case <-timeout:
+			t.Fatalf("timed out waiting for events. Saw: %v", seen)
+		}
+	}
+}
+
+func TestWatcherPerMountAllNative(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	notify, err := newWatcherPerMount(nil, []string{f.Path()}, EmptyMatcher{}, logger.NewTestLogger(bytes.NewBuffer(nil)))
+	require.NoError(t, err)
+	defer func() { _ = notify.Close() }()
+
+	_, isMulti := notify.(*multiNotify)
+	_, isPoll := notify.(*pollNotify)
+	assert.False(t, isMulti)
+	assert.False(t, isPoll)
+}
@@ -0,0 +1,153 @@
+package watch
+
+import (
+	"sort"
+	"sync"
+)
+
+// A Notify that merges the events and errors of several Notifys, so that
+// different watched paths can use different strategies (e.g., polling for
+// paths where OS file events don't work, and OS file events for the rest).
+type multiNotify struct {
+	inners []Notify
+	events chan FileEvent
+	errors chan error
+	stop   chan struct{}
+
+	closeOnce sync.Once
+}
+
+func newMultiNotify(inners ...Notify) *multiNotify {
+	return &multiNotify{
+		inners: inners,
+		events: make(chan FileEvent),
+		errors: make(chan error),
+		stop:   make(chan struct{}),
+	}
+}
+
+func (m *multiNotify) Start() error {
+	for i, inner := range m.inners {
+		err := inner.Start()
+		if err != nil {
+			for _, started := range m.inners[:i] {
+				_ = started.Close()
+			}
+			return err
+		}
+	}
+
+	var eventsWG, errorsWG sync.WaitGroup
+	for _, inner := range m.inners {
+		eventsWG.Add(1)
+		go func(events chan FileEvent) {
+			defer eventsWG.Done()
+			for {
+				select {
+				case <-m.stop:
+					return
+				case e, ok := <-events:
+					if !ok {
+						return
+					}
+					select {
+					case <-m.stop:
+						return
+					case m.events <- e:
+					}
+				}
+			}
+		}(inner.Events())
+
+		errorsWG.Add(1)
+		go func(errs chan error) {
+			defer errorsWG.Done()
+			for {
+				select {
+				case <-m.stop:
+					return
+				case err, ok := <-errs:
+					if !ok {
+						return
+					}
+					select {
+					case <-m.stop:
+						return
+					case m.errors <- err:
+					}
+				}
+			}
+		}(inner.Errors())
+	}
+
+	// The forwarders stop when the inner channels close or when we're closed,
+	// so that they don't block on a send no one's going to read.
+	go func() {
+		eventsWG.Wait()
+		close(m.events)
+	}()
+	go func() {
+		errorsWG.Wait()
+		close(m.errors)
+	}()
+	return nil
+}
+
+func (m *multiNotify) Close() error {
+	var result error
+	m.closeOnce.Do(func() { result = m.close() })
+	return result
+}
+
+func (m *multiNotify) close() error {
+	close(m.stop)
+	var result error
+	for _, inner := range m.inners {
+		err := inner.Close()
+		if err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func (m *multiNotify) Events() chan FileEvent {
+	return m.events
+}
+
+func (m *multiNotify) Errors() chan error {
+	return m.errors
+}
+
+func (m *multiNotify) WatchedPaths() []string {
+	var result []string
+	for _, inner := range m.inners {
+		if diag, ok := inner.(WatchDiagnostics); ok {
+			result = append(result, diag.WatchedPaths()...)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (m *multiNotify) WatchCount() int64 {
+	var result int64
+	for _, inner := range m.inners {
+		if diag, ok := inner.(WatchDiagnostics); ok {
+			result += diag.WatchCount()
+		}
+	}
+	return result
+}
+
+func (m *multiNotify) SetIgnore(ignore PathMatcher) {
+	for _, inner := range m.inners {
+		if setter, ok := inner.(IgnoreSetter); ok {
+			setter.SetIgnore(ignore)
+		}
+	}
+}
+
+var _ Notify = &multiNotify{}
+var _ WatchDiagnostics = &multiNotify{}
+var _ IgnoreSetter = &multiNotify{}
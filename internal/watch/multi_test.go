@@ -0,0 +1,95 @@
+package watch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type chanNotify struct {
+	events chan FileEvent
+	errors chan error
+}
+
+func newChanNotify() *chanNotify {
+	return &chanNotify{events: make(chan FileEvent, 10), errors: make(chan error, 10)}
+}
+
+func (n *chanNotify) Start() error           { return nil }
+func (n *chanNotify) Close() error           { return nil }
+func (n *chanNotify) Events() chan FileEvent { return n.events }
+func (n *chanNotify) Errors() chan error     { return n.errors }
+
+func TestMultiNotifyForwardsFromEachInner(t *testing.T) {
+	a, b := newChanNotify(), newChanNotify()
+	m := newMultiNotify(a, b)
+	require.NoError(t, m.Start())
+	defer m.Close()
+
+	a.events <- NewFileEvent("/a")
+	b.events <- NewFileEvent("/b")
+
+	var paths []string
+	for i := 0; i < 2; i++ {
+		select {
+		case e := <-m.Events():
+			paths = append(paths, e.Path())
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for events, got: %v", paths)
+		}
+	}
+	require.ElementsMatch(t, []string{"/a", "/b"}, paths)
+}
+
+func TestMultiNotifyStopsForwardingOnClose(t *testing.T) {
+	// The inner Notify never closes its channels, and no one reads ours,
+	// so only Close can unblock the forwarding goroutines.
+	inner := newChanNotify()
+	m := newMultiNotify(inner)
+	require.NoError(t, m.Start())
+
+	inner.events <- NewFileEvent("/a")
+	inner.errors <- nil
+	require.NoError(t, m.Close())
+
+	requireEventsClosedSoon(t, m.Events())
+	requireErrorsClosedSoon(t, m.Errors())
+}
+
+func TestMultiNotifyCloseTwice(t *testing.T) {
+	m := newMultiNotify(newChanNotify(), newChanNotify())
+	require.NoError(t, m.Start())
+	require.NoError(t, m.Close())
+	require.NoError(t, m.Close())
+}
+
+func requireEventsClosedSoon(t *testing.T, ch chan FileEvent) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for events channel to close")
+		}
+	}
+}
+
+func requireErrorsClosedSoon(t *testing.T, ch chan error) {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for errors channel to close")
+		}
+	}
+}
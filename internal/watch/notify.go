@@ -8,12 +8,19 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/tilt-dev/fsnotify"
 
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
 var (
 	numberOfWatches = expvar.NewInt("watch.naive.numberOfWatches")
+
+	// The number of times the OS told us it dropped file events because its
+	// event queue overflowed.
+	numberOfOverflows = expvar.NewInt("watch.naive.numberOfOverflows")
 )
 
 type FileEvent struct {
@@ -45,6 +52,30 @@ type Notify interface {
 	Errors() chan error
 }
 
+// Optional diagnostics for a Notify, to help users figure out which
+// directories are eating up their OS watch limits.
+type WatchDiagnostics interface {
+	// The paths the watcher has asked the OS to watch, sorted.
+	WatchedPaths() []string
+
+	// The number of OS-level watches this watcher holds.
+	WatchCount() int64
+}
+
+// Optional for a Notify: swap the ignore rules without restarting the watcher,
+// so that we don't drop events while we tear down and re-add OS watches.
+type IgnoreSetter interface {
+	SetIgnore(ignore PathMatcher)
+}
+
+// Optional for a Notify: read events off in batches, so that consumers
+// don't pay for a channel send per file when a git operation touches
+// thousands of files at once.
+type BatchNotify interface {
+	// A channel to read off groups of file changes that happened close together.
+	BatchedEvents() <-chan []FileEvent
+}
+
 // When we specify directories to watch, we often want to
 // ignore some subset of the files under those directories.
 //
@@ -69,7 +100,103 @@ func (EmptyMatcher) MatchesEntireDir(f string) (bool, error) { return false, nil
 var _ PathMatcher = EmptyMatcher{}
 
 func NewWatcher(paths []string, ignore PathMatcher, l logger.Logger) (Notify, error) {
-	return newWatcher(paths, ignore, l)
+	return newNotify(paths, ignore, false, l)
+}
+
+// NewDirWatcher is like NewWatcher, for consumers that only care that something
+// under a watched path changed, not which file. When TILT_WATCH_COALESCE_DIRS
+// is set, it reports the watched path instead of each file under it.
+func NewDirWatcher(paths []string, ignore PathMatcher, l logger.Logger) (Notify, error) {
+	return newNotify(paths, ignore, ShouldCoalesceDirs(), l)
+}
+
+func newNotify(paths []string, ignore PathMatcher, coalesce bool, l logger.Logger) (Notify, error) {
+	var notify Notify
+	var err error
+	if ShouldPoll() {
+		notify, err = newPollWatcher(paths, ignore, l, DesiredPollInterval())
+	} else if ShouldPollByMount() {
+		notify, err = newWatcherPerMount(readMountInfo(mountInfoPath), paths, ignore, l)
+	} else {
+		notify, err = newWatcher(paths, ignore, l)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	window := DesiredDebounceWindow()
+	if coalesce && window == 0 {
+		window = defaultCoalesceWindow
+	}
+	if window > 0 {
+		debounce := newDebounceNotify(notify, window)
+		if coalesce {
+			debounce.coalesce = nearestWatchedPath(paths)
+		}
+		notify = debounce
+	}
+	return newBatchNotify(notify, defaultBatchWindow, defaultMaxBatchSize), nil
+}
+
+// Uses OS file events where they work, and falls back to polling for paths on
+// mounts where they don't (e.g., read-only bind mounts or network filesystems).
+func newWatcherPerMount(mounts []mountInfo, paths []string, ignore PathMatcher, l logger.Logger) (Notify, error) {
+	pollPaths, nativePaths := splitPathsByWatchStrategy(mounts, paths)
+	if len(pollPaths) == 0 {
+		return newWatcher(paths, ignore, l)
+	}
+
+	l.Infof("OS file events don't work on the mounts for %s. Watching by polling every %s "+
+		"(set %s=0 to use OS file events anyway)",
+		strings.Join(pollPaths, ", "), DesiredPollInterval(), PollEnvVar)
+	poll, err := newPollWatcher(pollPaths, ignore, l, DesiredPollInterval())
+	if err != nil {
+		return nil, err
+	}
+	if len(nativePaths) == 0 {
+		return poll, nil
+	}
+
+	native, err := newWatcher(nativePaths, ignore, l)
+	if err != nil {
+		return nil, err
+	}
+	return newMultiNotify(native, poll), nil
+}
+
+// Set TILT_WATCH_POLL=1 to find file changes by polling instead of
+// OS file events. Useful on network filesystems where events are unreliable.
+//
+// By default, we only poll the watched paths on mounts where OS file events
+// don't work (e.g., read-only bind mounts or network filesystems). Set
+// TILT_WATCH_POLL=0 to use OS file events for those too.
+const PollEnvVar = "TILT_WATCH_POLL"
+
+// How often the polling watcher stats files, as a Go duration (e.g., "2s").
+const PollIntervalEnvVar = "TILT_WATCH_POLL_INTERVAL"
+
+const defaultPollInterval = time.Second
+
+func ShouldPoll() bool {
+	poll, err := strconv.ParseBool(os.Getenv(PollEnvVar))
+	return err == nil && poll
+}
+
+// True unless the user turned polling off explicitly.
+func ShouldPollByMount() bool {
+	poll, err := strconv.ParseBool(os.Getenv(PollEnvVar))
+	return err != nil || poll
+}
+
+func DesiredPollInterval() time.Duration {
+	envVar := os.Getenv(PollIntervalEnvVar)
+	if envVar != "" {
+		interval, err := time.ParseDuration(envVar)
+		if err == nil && interval > 0 {
+			return interval
+		}
+	}
+	return defaultPollInterval
 }
 
 const WindowsBufferSizeEnvVar = "TILT_WATCH_WINDOWS_BUFFER_SIZE"
@@ -87,6 +214,96 @@ func DesiredWindowsBufferSize() int {
 	return defaultBufferSize
 }
 
+// Set TILT_WATCH_FOLLOW_SYMLINKS=1 to watch directories behind symlinks
+// in a watched tree (e.g., shared packages symlinked into a monorepo service).
+//
+// Only supported by watchers that walk the tree themselves (i.e., on Linux).
+const FollowSymlinksEnvVar = "TILT_WATCH_FOLLOW_SYMLINKS"
+
+func ShouldFollowSymlinks() bool {
+	follow, err := strconv.ParseBool(os.Getenv(FollowSymlinksEnvVar))
+	return err == nil && follow
+}
+
+// Set TILT_WATCH_MAX_DEPTH=N to stop watching directories more than N levels
+// below a watched path. Changes deeper than that won't be seen, so use ignores
+// to exclude the deep trees you don't care about (e.g., node_modules).
+//
+// Only supported by watchers that walk the tree themselves (i.e., on Linux).
+const MaxDepthEnvVar = "TILT_WATCH_MAX_DEPTH"
+
+// Returns 0 (i.e., no limit) unless the env var is set to a positive number.
+func DesiredMaxDepth() int {
+	envVar := os.Getenv(MaxDepthEnvVar)
+	if envVar != "" {
+		depth, err := strconv.Atoi(envVar)
+		if err == nil && depth > 0 {
+			return depth
+		}
+	}
+	return 0
+}
+
+// Set TILT_WATCH_SKIP_UNCHANGED=1 to skip events for writes that leave a file's
+// content as it was (e.g., editors that re-save files when they lose focus).
+// Costs a read of every file that changes.
+//
+// Only supported by the naive watcher (i.e., not on macOS).
+const SkipUnchangedEnvVar = "TILT_WATCH_SKIP_UNCHANGED"
+
+func ShouldSkipUnchanged() bool {
+	skip, err := strconv.ParseBool(os.Getenv(SkipUnchangedEnvVar))
+	return err == nil && skip
+}
+
+// Set TILT_WATCH_STATIC_DIRS=1 to only watch the directories that exist when
+// we start watching. We still report that a new directory was created, but
+// we won't see changes inside it. Keeps a build tool that writes a huge output
+// directory mid-session from blowing up the number of watches.
+//
+// Only supported by watchers that walk the tree themselves (i.e., on Linux).
+const StaticDirsEnvVar = "TILT_WATCH_STATIC_DIRS"
+
+func ShouldWatchStaticDirs() bool {
+	static, err := strconv.ParseBool(os.Getenv(StaticDirsEnvVar))
+	return err == nil && static
+}
+
+// Set TILT_WATCH_FORCE_INCLUDE to a list of paths (separated like $PATH) to
+// watch even when an ignore would skip them (e.g., a .config directory under
+// an ignored tree of dotfiles). Changes inside these paths are reported as long
+// as they're under a watched path.
+const ForceIncludeEnvVar = "TILT_WATCH_FORCE_INCLUDE"
+
+func DesiredForceIncludePaths() []string {
+	var result []string
+	for _, p := range filepath.SplitList(os.Getenv(ForceIncludeEnvVar)) {
+		if p == "" {
+			continue
+		}
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			continue
+		}
+		result = append(result, abs)
+	}
+	return result
+}
+
 func IsWindowsShortReadError(err error) bool {
 	return runtime.GOOS == "windows" && err != nil && strings.Contains(err.Error(), "short read")
 }
+
+// IsOverflowError is true if the OS dropped file events because they came in
+// faster than we read them: inotify's queue overflowed on Linux, or the
+// change buffer was too small on Windows.
+func IsOverflowError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if err.Error() == fsnotify.ErrEventOverflow.Error() {
+		return true
+	}
+	return runtime.GOOS == "windows" &&
+		(strings.Contains(err.Error(), "short read") || strings.Contains(err.Error(), "events have likely been missed"))
+}
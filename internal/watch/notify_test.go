@@ -490,6 +490,30 @@ func TestWatchCountInnerFile(t *testing.T) {
 	assert.Equal(t, expectedWatches, int(numberOfWatches.Value()))
 }
 
+func TestWatchDiagnostics(t *testing.T) {
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.paths[0]
+	a := f.JoinPath(root, "a")
+	b := f.JoinPath(a, "b")
+	file := f.JoinPath(b, "bigFile")
+	f.WriteFile(file, "hello")
+	f.assertEvents(a, b, file)
+
+	diag, ok := f.notify.(WatchDiagnostics)
+	if !ok {
+		t.Fatalf("watcher %T doesn't implement WatchDiagnostics", f.notify)
+	}
+
+	expectedPaths := []string{root, a, b}
+	if isRecursiveWatcher() {
+		expectedPaths = []string{root}
+	}
+	assert.Equal(t, expectedPaths, diag.WatchedPaths())
+	assert.Equal(t, numberOfWatches.Value(), diag.WatchCount())
+}
+
 func TestWatchCountInnerFileWithIgnore(t *testing.T) {
 	f := newNotifyFixture(t)
 	defer f.tearDown()
@@ -584,6 +608,88 @@ func TestIgnoreInitialDir(t *testing.T) {
 	assert.Equal(t, expectedWatches, int(numberOfWatches.Value()))
 }
 
+func TestForceIncludeOverridesIgnore(t *testing.T) {
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.TempDir("root")
+	app := f.JoinPath(root, ".config", "app")
+	other := f.JoinPath(root, ".config", "other")
+	f.MkdirAll(app)
+	f.MkdirAll(other)
+	setForceInclude(t, app)
+
+	ignore, _ := dockerignore.NewDockerPatternMatcher(root, []string{".*"})
+	f.setIgnore(ignore)
+	f.watch(root)
+
+	// Only the force-included subtree overrides the ignore, not its
+	// ignored siblings or parent.
+	f.WriteFile(f.JoinPath(other, "ignored.yaml"), "hello")
+	f.WriteFile(f.JoinPath(root, ".config", "ignored.yaml"), "hello")
+	settings := f.JoinPath(app, "settings.yaml")
+	f.WriteFile(settings, "hello")
+	f.assertEvents(settings)
+}
+
+func TestForceIncludeOutsideWatchedPaths(t *testing.T) {
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.TempDir("root")
+	unwatched := f.TempDir("unwatched")
+	setForceInclude(t, unwatched)
+	f.watch(root)
+
+	f.WriteFile(filepath.Join(unwatched, "a.txt"), "hello")
+	f.assertEvents()
+}
+
+func setForceInclude(t *testing.T, paths ...string) {
+	orig := os.Getenv(ForceIncludeEnvVar)
+	t.Cleanup(func() { os.Setenv(ForceIncludeEnvVar, orig) })
+	os.Setenv(ForceIncludeEnvVar, strings.Join(paths, string(filepath.ListSeparator)))
+}
+
+func TestSetIgnoreStopsIgnoring(t *testing.T) {
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.TempDir("root")
+	a := f.JoinPath(root, "a")
+	f.MkdirAll(a)
+
+	ignore, _ := dockerignore.NewDockerPatternMatcher(root, []string{"a"})
+	f.setIgnore(ignore)
+	f.watch(root)
+
+	f.WriteFile(f.JoinPath(a, "ignored"), "hello")
+	f.assertEvents()
+
+	f.setIgnoreInPlace(EmptyMatcher{})
+
+	file := f.JoinPath(a, "bigFile")
+	f.WriteFile(file, "hello")
+	f.assertEvents(file)
+}
+
+func TestSetIgnoreStartsIgnoring(t *testing.T) {
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.paths[0]
+	a := f.JoinPath(root, "a")
+	f.MkdirAll(a)
+	f.assertEvents(a)
+	f.events = nil
+
+	ignore, _ := dockerignore.NewDockerPatternMatcher(root, []string{"a"})
+	f.setIgnoreInPlace(ignore)
+
+	f.WriteFile(f.JoinPath(a, "bigFile"), "hello")
+	f.assertEvents()
+}
+
 func isRecursiveWatcher() bool {
 	return runtime.GOOS == "darwin" || runtime.GOOS == "windows"
 }
@@ -619,6 +725,13 @@ func (f *notifyFixture) setIgnore(ignore PathMatcher) {
 	f.rebuildWatcher()
 }
 
+// Swap the ignore rules on the running watcher, without rebuilding it.
+func (f *notifyFixture) setIgnoreInPlace(ignore PathMatcher) {
+	f.fsync()
+	f.ignore = ignore
+	f.notify.(IgnoreSetter).SetIgnore(ignore)
+}
+
 func (f *notifyFixture) watch(path string) {
 	f.paths = append(f.paths, path)
 	f.rebuildWatcher()
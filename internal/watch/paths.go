@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
 
@@ -67,3 +68,35 @@ func dedupePathsForRecursiveWatcher(paths []string) []string {
 	}
 	return result
 }
+
+// Force-included paths override ignores. We have to walk into the ancestors
+// of a force-included path to reach it, but we only report changes inside it.
+type forceIncludeList []string
+
+// True if path is a force-included path or inside one.
+func (l forceIncludeList) includes(path string) bool {
+	for _, p := range l {
+		if ospath.IsChild(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// True if we can't skip the directory at path without missing a
+// force-included path.
+func (l forceIncludeList) mustWalk(dir string) bool {
+	for _, p := range l {
+		if ospath.IsChild(p, dir) || ospath.IsChild(dir, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// A cheap check that rules out most pairs of paths before we call
+// ospath.IsChild, which cleans both paths on every call. Only valid for
+// clean paths.
+func mayBeChild(dir, file string) bool {
+	return len(file) >= len(dir) && strings.EqualFold(file[:len(dir)], dir)
+}
@@ -1,6 +1,7 @@
 package watch
 
 import (
+	"path/filepath"
 	"runtime"
 	"testing"
 
@@ -28,3 +29,37 @@ func TestGreatestExistingAncestor(t *testing.T) {
 	_, err = greatestExistingAncestor(missingTopLevel)
 	assert.Contains(t, err.Error(), "cannot watch root directory")
 }
+
+func TestForceIncludeList(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	l := forceIncludeList{f.JoinPath(".config", "app")}
+
+	assert.True(t, l.includes(f.JoinPath(".config", "app")))
+	assert.True(t, l.includes(f.JoinPath(".config", "app", "settings.yaml")))
+	assert.False(t, l.includes(f.JoinPath(".config")))
+	assert.False(t, l.includes(f.JoinPath(".config", "application")))
+
+	// We have to walk through the ancestors to reach it.
+	assert.True(t, l.mustWalk(f.Path()))
+	assert.True(t, l.mustWalk(f.JoinPath(".config")))
+	assert.True(t, l.mustWalk(f.JoinPath(".config", "app", "nested")))
+	assert.False(t, l.mustWalk(f.JoinPath(".config", "other")))
+	assert.False(t, l.mustWalk(f.JoinPath(".cache")))
+}
+
+func TestDesiredForceIncludePaths(t *testing.T) {
+	setForceInclude(t, "", "a", "/b/c")
+	a, _ := filepath.Abs("a")
+	bc, _ := filepath.Abs("/b/c")
+	assert.Equal(t, []string{a, bc}, DesiredForceIncludePaths())
+}
+
+func TestMayBeChild(t *testing.T) {
+	assert.True(t, mayBeChild("/src", "/src"))
+	assert.True(t, mayBeChild("/src", "/src/app"))
+	assert.True(t, mayBeChild("/src", "/SRC/app"))
+	assert.False(t, mayBeChild("/src/app", "/src"))
+	assert.False(t, mayBeChild("/src", "/lib/app"))
+}
@@ -2,6 +2,8 @@ package watch
 
 import (
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -20,7 +22,14 @@ type darwinNotify struct {
 	stop   chan struct{}
 
 	pathsWereWatching map[string]interface{}
-	ignore            PathMatcher
+
+	// Guards ignore, which SetIgnore can swap while we're watching.
+	ignoreMu sync.RWMutex
+	ignore   PathMatcher
+
+	// Paths we watch even if the ignores would skip them.
+	forceInclude forceIncludeList
+
 	logger            logger.Logger
 	sawAnyHistoryDone bool
 }
@@ -56,11 +65,13 @@ func (d *darwinNotify) loop() {
 					continue
 				}
 
-				ignore, err := d.ignore.Matches(e.Path)
-				if err != nil {
-					d.logger.Infof("Error matching path %q: %v", e.Path, err)
-				} else if ignore {
-					continue
+				if !d.forceInclude.includes(e.Path) {
+					ignore, err := d.getIgnore().Matches(e.Path)
+					if err != nil {
+						d.logger.Infof("Error matching path %q: %v", e.Path, err)
+					} else if ignore {
+						continue
+					}
 				}
 
 				d.events <- NewFileEvent(e.Path)
@@ -69,6 +80,20 @@ func (d *darwinNotify) loop() {
 	}
 }
 
+// Swaps the ignore rules while we're watching. FSEvents watches
+// recursively, so there are no OS watches to update.
+func (d *darwinNotify) SetIgnore(ignore PathMatcher) {
+	d.ignoreMu.Lock()
+	defer d.ignoreMu.Unlock()
+	d.ignore = ignore
+}
+
+func (d *darwinNotify) getIgnore() PathMatcher {
+	d.ignoreMu.RLock()
+	defer d.ignoreMu.RUnlock()
+	return d.ignore
+}
+
 // Add a path to be watched. Should only be called during initialization.
 func (d *darwinNotify) initAdd(name string) {
 	d.stream.Paths = append(d.stream.Paths, name)
@@ -111,10 +136,21 @@ func (d *darwinNotify) Errors() chan error {
 	return d.errors
 }
 
+func (d *darwinNotify) WatchedPaths() []string {
+	result := append([]string{}, d.stream.Paths...)
+	sort.Strings(result)
+	return result
+}
+
+func (d *darwinNotify) WatchCount() int64 {
+	return int64(len(d.stream.Paths))
+}
+
 func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*darwinNotify, error) {
 	dw := &darwinNotify{
-		ignore: ignore,
-		logger: l,
+		ignore:       ignore,
+		forceInclude: DesiredForceIncludePaths(),
+		logger:       l,
 		stream: &fsevents.EventStream{
 			Latency: 1 * time.Millisecond,
 			Flags:   fsevents.FileEvents,
@@ -140,3 +176,5 @@ func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*darwinNot
 }
 
 var _ Notify = &darwinNotify{}
+var _ WatchDiagnostics = &darwinNotify{}
+var _ IgnoreSetter = &darwinNotify{}
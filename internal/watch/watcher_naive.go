@@ -8,7 +8,11 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tilt-dev/fsnotify"
@@ -27,35 +31,69 @@ type naiveNotify struct {
 	// in order to fulfill the API promise.
 	notifyList map[string]bool
 
-	ignore PathMatcher
-	log    logger.Logger
+	// Guards ignore, which SetIgnore can swap while we're watching.
+	// Matchers aren't safe for concurrent use (they compile their patterns lazily),
+	// so we hold this while matching, too.
+	ignoreMu sync.Mutex
+	ignore   PathMatcher
+
+	log logger.Logger
 
 	isWatcherRecursive bool
 	watcher            *fsnotify.Watcher
 	events             chan fsnotify.Event
 	wrappedEvents      chan FileEvent
 	errors             chan error
-	numWatches         int64
+
+	// Guards numWatches, watchedPaths, and followedRealPaths, which diagnostics
+	// and SetIgnore touch from other goroutines.
+	mu         sync.Mutex
+	numWatches int64
+
+	// The paths we've added fsnotify watches for.
+	watchedPaths map[string]bool
+
+	// When true, we watch directories behind symlinks. Events are reported
+	// under the symlink's path, not the target's.
+	followSymlinks bool
+
+	// When positive, we don't watch directories more than this many levels
+	// below a path in the notify list.
+	maxDepth int
+
+	// When true, we don't add watches for directories created after we start.
+	staticDirs bool
+
+	// Paths we watch even if the ignores would skip them.
+	forceInclude forceIncludeList
+
+	// When non-nil, we skip write events that didn't change the file's content.
+	// Only touched by the loop goroutine.
+	contentHashes *contentHashCache
+
+	// Real paths of the directories we've followed, so we don't get stuck in symlink cycles.
+	followedRealPaths map[string]bool
+
+	// When we last warned about an overflow. Only touched by the error loop.
+	lastOverflowWarning time.Time
 }
 
+// Overflows tend to come in bursts, so we don't warn about every one.
+const overflowWarningInterval = time.Minute
+
 func (d *naiveNotify) Start() error {
 	if len(d.notifyList) == 0 {
 		return nil
 	}
 
-	pathsToWatch := []string{}
-	for path := range d.notifyList {
-		pathsToWatch = append(pathsToWatch, path)
-	}
-
-	pathsToWatch, err := greatestExistingAncestors(pathsToWatch)
+	pathsToWatch, err := d.rootsToWatch()
 	if err != nil {
 		return err
 	}
-	if d.isWatcherRecursive {
-		pathsToWatch = dedupePathsForRecursiveWatcher(pathsToWatch)
-	}
 
+	// Watch each directory once, even if we're watching many files in it.
+	var fileDirs []string
+	seenFileDirs := make(map[string]bool)
 	for _, name := range pathsToWatch {
 		fi, err := os.Stat(name)
 		if err != nil && !os.IsNotExist(err) {
@@ -67,15 +105,24 @@ func (d *naiveNotify) Start() error {
 		if os.IsNotExist(err) {
 			continue
 		} else if fi.IsDir() {
+			d.markFollowed(name)
 			err = d.watchRecursively(name)
 			if err != nil {
-				return errors.Wrapf(err, "notify.Add(%q)", name)
-			}
-		} else {
-			err = d.add(filepath.Dir(name))
-			if err != nil {
-				return errors.Wrapf(err, "notify.Add(%q)", filepath.Dir(name))
+				return d.explainWatchLimit(errors.Wrapf(err, "notify.Add(%q)", name), pathsToWatch)
 			}
+		} else if dir := filepath.Dir(name); !seenFileDirs[dir] {
+			seenFileDirs[dir] = true
+			fileDirs = append(fileDirs, dir)
+		}
+	}
+
+	for _, dir := range fileDirs {
+		if d.isWatched(dir) {
+			continue
+		}
+		err = d.add(dir)
+		if err != nil {
+			return d.explainWatchLimit(errors.Wrapf(err, "notify.Add(%q)", dir), pathsToWatch)
 		}
 	}
 
@@ -84,6 +131,102 @@ func (d *naiveNotify) Start() error {
 	return nil
 }
 
+// The paths we add watches under: the closest existing ancestor of every path
+// in the notify list.
+func (d *naiveNotify) rootsToWatch() ([]string, error) {
+	pathsToWatch := []string{}
+	for path := range d.notifyList {
+		pathsToWatch = append(pathsToWatch, path)
+	}
+
+	pathsToWatch, err := greatestExistingAncestors(pathsToWatch)
+	if err != nil {
+		return nil, err
+	}
+	if d.isWatcherRecursive {
+		pathsToWatch = dedupePathsForRecursiveWatcher(pathsToWatch)
+	}
+	return pathsToWatch, nil
+}
+
+// Swaps the ignore rules while we're watching.
+//
+// If the watcher isn't recursive, we walk the watched trees again to add
+// watches for directories that the old rules skipped. Directories that the
+// new rules skip stay watched until we close; we just stop reporting their events.
+func (d *naiveNotify) SetIgnore(ignore PathMatcher) {
+	d.ignoreMu.Lock()
+	d.ignore = ignore
+	d.ignoreMu.Unlock()
+
+	if d.isWatcherRecursive {
+		return
+	}
+
+	pathsToWatch, err := d.rootsToWatch()
+	if err != nil {
+		d.log.Infof("Error updating ignores: %v", err)
+		return
+	}
+
+	for _, name := range pathsToWatch {
+		fi, err := os.Stat(name)
+		if err != nil || !fi.IsDir() {
+			continue
+		}
+		err = d.watchRecursively(name)
+		if err != nil && !os.IsNotExist(err) {
+			d.log.Infof("Error watching path %s: %v", name, err)
+		}
+	}
+}
+
+func (d *naiveNotify) matchesIgnore(path string) (bool, error) {
+	d.ignoreMu.Lock()
+	defer d.ignoreMu.Unlock()
+	return d.ignore.Matches(path)
+}
+
+func (d *naiveNotify) matchesEntireIgnoredDir(path string) (bool, error) {
+	d.ignoreMu.Lock()
+	defer d.ignoreMu.Unlock()
+	return d.ignore.MatchesEntireDir(path)
+}
+
+// inotify reports ENOSPC when we've run out of watches. Replace it
+// with an error that tells the user how to raise the limit.
+func (d *naiveNotify) explainWatchLimit(err error, pathsToWatch []string) error {
+	if runtime.GOOS != "linux" || !errors.Is(err, syscall.ENOSPC) {
+		return err
+	}
+
+	// Tilt's other watchers hold some watches, and this watcher wants one per directory.
+	d.mu.Lock()
+	needed := numberOfWatches.Value() - d.numWatches
+	d.mu.Unlock()
+	needed += int64(d.countDirsToWatch(pathsToWatch))
+	return inotifyLimitError(inotifyProcDir, inotifyMaxUserWatches, int(needed))
+}
+
+// Count the directories under the given paths that we'd add watches for.
+func (d *naiveNotify) countDirsToWatch(paths []string) int {
+	count := 0
+	for _, root := range paths {
+		_ = filepath.WalkDir(root, func(path string, info fs.DirEntry, err error) error {
+			if err != nil || !info.IsDir() {
+				return nil
+			}
+			shouldSkipDir, err := d.shouldSkipDir(path)
+			if err == nil && shouldSkipDir {
+				return filepath.SkipDir
+			}
+			count++
+			return nil
+		})
+	}
+	return count
+}
+
 func (d *naiveNotify) watchRecursively(dir string) error {
 	if d.isWatcherRecursive {
 		err := d.add(dir)
@@ -98,8 +241,11 @@ func (d *naiveNotify) watchRecursively(dir string) error {
 			return err
 		}
 
+		// When we walk through a symlink, the root has a trailing separator.
+		path = filepath.Clean(path)
+
 		if !info.IsDir() {
-			return nil
+			return d.maybeFollowSymlink(path, info)
 		}
 
 		shouldSkipDir, err := d.shouldSkipDir(path)
@@ -123,11 +269,31 @@ func (d *naiveNotify) watchRecursively(dir string) error {
 }
 
 func (d *naiveNotify) Close() error {
+	d.mu.Lock()
 	numberOfWatches.Add(-d.numWatches)
 	d.numWatches = 0
+	d.watchedPaths = make(map[string]bool)
+	d.mu.Unlock()
 	return d.watcher.Close()
 }
 
+func (d *naiveNotify) WatchedPaths() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	result := make([]string, 0, len(d.watchedPaths))
+	for path := range d.watchedPaths {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (d *naiveNotify) WatchCount() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.numWatches
+}
+
 func (d *naiveNotify) Events() chan FileEvent {
 	return d.wrappedEvents
 }
@@ -136,6 +302,29 @@ func (d *naiveNotify) Errors() chan error {
 	return d.errors
 }
 
+// Passes errors through to the caller, keeping track of overflows along the way.
+func (d *naiveNotify) errorLoop(errs <-chan error) {
+	defer close(d.errors)
+	for err := range errs {
+		if IsOverflowError(err) {
+			d.recordOverflow()
+		}
+		d.errors <- err
+	}
+}
+
+func (d *naiveNotify) recordOverflow() {
+	numberOfOverflows.Add(1)
+
+	now := time.Now()
+	if now.Sub(d.lastOverflowWarning) < overflowWarningInterval {
+		return
+	}
+	d.lastOverflowWarning = now
+	d.log.Infof("Warning: the OS dropped file events because they came in too fast "+
+		"(%d overflow(s) so far). Some file changes may have been missed.", numberOfOverflows.Value())
+}
+
 func (d *naiveNotify) loop() {
 	defer close(d.wrappedEvents)
 	for e := range d.events {
@@ -145,8 +334,14 @@ func (d *naiveNotify) loop() {
 			continue
 		}
 
+		if e.Op&fsnotify.Rename == fsnotify.Rename {
+			d.removeStaleWatches(e.Name)
+		}
+
 		if e.Op&fsnotify.Create != fsnotify.Create {
-			if d.shouldNotify(e.Name) {
+			// For renames, this reports the old path, which no longer exists.
+			// The new path (if we're watching it) comes in as its own Create event.
+			if d.shouldNotify(e.Name) && d.contentChanged(e) {
 				d.wrappedEvents <- FileEvent{e.Name}
 			}
 			continue
@@ -154,26 +349,44 @@ func (d *naiveNotify) loop() {
 
 		if d.isWatcherRecursive {
 			if d.shouldNotify(e.Name) {
+				d.recordContent(e.Name)
 				d.wrappedEvents <- FileEvent{e.Name}
 			}
 			continue
 		}
 
 		// If the watcher is not recursive, we have to walk the tree
-		// and add watches manually. We fire the event while we're walking the tree.
-		// because it's a bit more elegant that way.
+		// and add watches manually. We collect the events while we're walking the tree,
+		// then fire them sorted by path, so that the order doesn't depend on the walk.
 		//
 		// TODO(dbentley): if there's a delete should we call d.watcher.Remove to prevent leaking?
+		var toNotify []string
 		err := filepath.WalkDir(e.Name, func(path string, info fs.DirEntry, err error) error {
 			if err != nil {
 				return err
 			}
 
+			path = filepath.Clean(path)
 			if d.shouldNotify(path) {
-				d.wrappedEvents <- FileEvent{path}
+				if !info.IsDir() {
+					d.recordContent(path)
+				}
+				toNotify = append(toNotify, path)
 			}
 
-			// TODO(dmiller): symlinks 😭
+			if d.staticDirs && !d.isNotifyPathOrAncestor(path) && (info.IsDir() || info.Type()&fs.ModeSymlink != 0) {
+				// Only watch the directories we found at startup, plus the
+				// paths we were asked to watch that didn't exist yet.
+				if info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			err = d.maybeFollowSymlink(path, info)
+			if err != nil {
+				d.log.Infof("Error following symlink %s: %s", path, err)
+			}
 
 			shouldWatch := false
 			if info.IsDir() {
@@ -205,29 +418,92 @@ func (d *naiveNotify) loop() {
 		if err != nil && !os.IsNotExist(err) {
 			d.log.Infof("Error walking directory %s: %s", e.Name, err)
 		}
+
+		sort.Strings(toNotify)
+		for _, path := range toNotify {
+			d.wrappedEvents <- FileEvent{path}
+		}
 	}
 }
 
+// Returns false if the event is a write that left the file's content as it was.
+func (d *naiveNotify) contentChanged(e fsnotify.Event) bool {
+	if d.contentHashes == nil {
+		return true
+	}
+
+	if e.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+		d.contentHashes.remove(e.Name)
+		return true
+	}
+	if e.Op != fsnotify.Write {
+		return true
+	}
+	return d.contentHashes.update(e.Name)
+}
+
+// Remember the content of a file we've just seen created, so that we can
+// tell if later writes change it.
+func (d *naiveNotify) recordContent(path string) {
+	if d.contentHashes == nil {
+		return
+	}
+	d.contentHashes.update(path)
+}
+
 func (d *naiveNotify) shouldNotify(path string) bool {
-	ignore, err := d.ignore.Matches(path)
-	if err != nil {
-		d.log.Infof("Error matching path %q: %v", path, err)
-	} else if ignore {
+	// Check the notify list before the ignores, which are slower to match. When
+	// we watch individual files, most events are for their siblings.
+	isRoot := d.notifyList[path]
+	if !isRoot && !d.isUnderNotifyPath(path) {
 		return false
 	}
 
-	if _, ok := d.notifyList[path]; ok {
+	if !d.forceInclude.includes(path) {
+		ignore, err := d.matchesIgnore(path)
+		if err != nil {
+			d.log.Infof("Error matching path %q: %v", path, err)
+		} else if ignore {
+			return false
+		}
+	}
+
+	if isRoot {
 		// We generally don't care when directories change at the root of an ADD
 		stat, err := os.Lstat(path)
 		isDir := err == nil && stat.IsDir()
-		if isDir {
-			return false
-		}
-		return true
+		return !isDir
 	}
+	return true
+}
+
+func (d *naiveNotify) isUnderNotifyPath(path string) bool {
 	// TODO(dmiller): maybe use a prefix tree here?
 	for root := range d.notifyList {
-		if ospath.IsChild(root, path) {
+		if mayBeChild(root, path) && ospath.IsChild(root, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// True if the directory is in a path in the notify list, or is an ancestor we
+// have to watch to see one created (or to see changes to a file in it).
+func (d *naiveNotify) leadsToNotifyPath(dir string) bool {
+	for root := range d.notifyList {
+		if (mayBeChild(root, dir) && ospath.IsChild(root, dir)) ||
+			(mayBeChild(dir, root) && ospath.IsChild(dir, root)) {
+			return true
+		}
+	}
+	return false
+}
+
+// True if the path is in the notify list, or is an ancestor we have to watch
+// to see one created.
+func (d *naiveNotify) isNotifyPathOrAncestor(path string) bool {
+	for root := range d.notifyList {
+		if mayBeChild(path, root) && ospath.IsChild(path, root) {
 			return true
 		}
 	}
@@ -240,24 +516,163 @@ func (d *naiveNotify) shouldSkipDir(path string) (bool, error) {
 		return false, nil
 	}
 
-	skip, err := d.ignore.MatchesEntireDir(path)
+	// Don't watch the siblings of the paths we're waiting on.
+	if !d.leadsToNotifyPath(path) {
+		return true, nil
+	}
+
+	if d.maxDepth > 0 && d.depth(path) > d.maxDepth {
+		return true, nil
+	}
+
+	if d.forceInclude.mustWalk(path) {
+		return false, nil
+	}
+
+	skip, err := d.matchesEntireIgnoredDir(path)
 	if err != nil {
 		return false, errors.Wrap(err, "shouldSkipDir")
 	}
 	return skip, nil
 }
 
+// How many levels below the closest path in the notify list this path is.
+// Ancestors that we watch while waiting for a path to be created are at depth 0.
+func (d *naiveNotify) depth(path string) int {
+	result := -1
+	for root := range d.notifyList {
+		if !ospath.IsChild(root, path) {
+			continue
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			continue
+		}
+		depth := 0
+		if rel != "." {
+			depth = len(strings.Split(rel, string(filepath.Separator)))
+		}
+		if result == -1 || depth < result {
+			result = depth
+		}
+	}
+	if result == -1 {
+		return 0
+	}
+	return result
+}
+
+// If path is a symlink to a directory that we haven't watched yet,
+// watch that directory through the symlink.
+func (d *naiveNotify) maybeFollowSymlink(path string, entry fs.DirEntry) error {
+	if !d.followSymlinks || entry.Type()&fs.ModeSymlink == 0 {
+		return nil
+	}
+
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		// Dangling symlinks are fine, there's just nothing to watch.
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "following symlink %q", path)
+	}
+
+	fi, err := os.Stat(realPath)
+	if err != nil || !fi.IsDir() {
+		return nil
+	}
+
+	shouldSkipDir, err := d.shouldSkipDir(path)
+	if err != nil || shouldSkipDir {
+		return err
+	}
+
+	d.mu.Lock()
+	followed := d.followedRealPaths[realPath]
+	d.followedRealPaths[realPath] = true
+	d.mu.Unlock()
+	if followed {
+		return nil
+	}
+
+	// The trailing separator makes WalkDir descend into the symlink's target.
+	return d.watchRecursively(path + string(filepath.Separator))
+}
+
+// Record that we're watching this directory, so that a symlink
+// back into it doesn't make us watch it again.
+func (d *naiveNotify) markFollowed(dir string) {
+	if !d.followSymlinks {
+		return
+	}
+	realPath, err := filepath.EvalSymlinks(dir)
+	if err == nil {
+		d.mu.Lock()
+		d.followedRealPaths[realPath] = true
+		d.mu.Unlock()
+	}
+}
+
+func (d *naiveNotify) isWatched(path string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.watchedPaths[path]
+}
+
 func (d *naiveNotify) add(path string) error {
 	err := d.watcher.Add(path)
 	if err != nil {
 		return err
 	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Adding a watch we already hold just refreshes it, so don't count it twice.
+	if d.watchedPaths[path] {
+		return nil
+	}
 	d.numWatches++
 	numberOfWatches.Add(1)
+	d.watchedPaths[path] = true
 	return nil
 }
 
-func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*naiveNotify, error) {
+// When a watched directory is renamed, inotify keeps watching the directory
+// under its new name, but fsnotify keeps reporting events with the old name.
+// If the directory has moved out of the watched tree, we'd report changes
+// to files that don't exist. Remove the watches on the old path and
+// everything below it; if the new path is watched, its Create event adds
+// fresh watches.
+func (d *naiveNotify) removeStaleWatches(oldPath string) {
+	if d.isWatcherRecursive {
+		return
+	}
+
+	_, err := os.Lstat(oldPath)
+	if !os.IsNotExist(err) {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for path := range d.watchedPaths {
+		if path != oldPath && !ospath.IsChild(oldPath, path) {
+			continue
+		}
+
+		delete(d.watchedPaths, path)
+		err := d.watcher.Remove(path)
+		if err != nil {
+			// The kernel may have already dropped the watch.
+			continue
+		}
+		d.numWatches--
+		numberOfWatches.Add(-1)
+	}
+}
+
+func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (Notify, error) {
 	if ignore == nil {
 		return nil, fmt.Errorf("newWatcher: ignore is nil")
 	}
@@ -265,11 +680,14 @@ func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*naiveNoti
 	fsw, err := fsnotify.NewWatcher()
 	if err != nil {
 		if strings.Contains(err.Error(), "too many open files") && runtime.GOOS == "linux" {
-			return nil, fmt.Errorf("Hit OS limits creating a watcher.\n" +
-				"Run 'sysctl fs.inotify.max_user_instances' to check your inotify limits.\n" +
-				"To raise them, run 'sudo sysctl fs.inotify.max_user_instances=1024'")
+			err = inotifyLimitError(inotifyProcDir, inotifyMaxUserInstances, 0)
+		} else {
+			err = errors.Wrap(err, "creating file watcher")
 		}
-		return nil, errors.Wrap(err, "creating file watcher")
+
+		// Polling is slow, but it's better than not seeing file changes at all.
+		l.Infof("Warning: %v\nFalling back to polling for file changes.", err)
+		return newPollWatcher(paths, ignore, l, DesiredPollInterval())
 	}
 	MaybeIncreaseBufferSize(fsw)
 
@@ -289,6 +707,11 @@ func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*naiveNoti
 		notifyList[path] = true
 	}
 
+	var contentHashes *contentHashCache
+	if ShouldSkipUnchanged() {
+		contentHashes = newContentHashCache(maxContentHashes)
+	}
+
 	wmw := &naiveNotify{
 		notifyList:         notifyList,
 		ignore:             ignore,
@@ -296,14 +719,24 @@ func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*naiveNoti
 		watcher:            fsw,
 		events:             fsw.Events,
 		wrappedEvents:      wrappedEvents,
-		errors:             fsw.Errors,
+		errors:             make(chan error),
 		isWatcherRecursive: isWatcherRecursive,
+		watchedPaths:       make(map[string]bool),
+		followSymlinks:     ShouldFollowSymlinks(),
+		maxDepth:           DesiredMaxDepth(),
+		staticDirs:         ShouldWatchStaticDirs(),
+		forceInclude:       DesiredForceIncludePaths(),
+		contentHashes:      contentHashes,
+		followedRealPaths:  make(map[string]bool),
 	}
+	go wmw.errorLoop(fsw.Errors)
 
 	return wmw, nil
 }
 
 var _ Notify = &naiveNotify{}
+var _ WatchDiagnostics = &naiveNotify{}
+var _ IgnoreSetter = &naiveNotify{}
 
 func greatestExistingAncestors(paths []string) ([]string, error) {
 	result := []string{}
@@ -0,0 +1,52 @@
+// +build !darwin
+
+package watch
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Watches a curated list of config files scattered across a large tree, and
+// reports how many OS-level watches that takes.
+func BenchmarkWatchScatteredFiles(b *testing.B) {
+	f := tempdir.NewTempDirFixture(b)
+	defer f.TearDown()
+
+	var paths []string
+	for i := 0; i < 20; i++ {
+		service := f.JoinPath(fmt.Sprintf("service%d", i))
+		for j := 0; j < 10; j++ {
+			f.WriteFile(filepath.Join(service, fmt.Sprintf("pkg%d", j), "main.go"), "package main")
+		}
+		config := filepath.Join(service, "config.yaml")
+		secrets := filepath.Join(service, "secrets.yaml")
+		f.WriteFile(config, "a: b")
+		f.WriteFile(secrets, "c: d")
+		paths = append(paths, config, secrets)
+	}
+	// Not created yet, so we watch its closest existing ancestor.
+	paths = append(paths, f.JoinPath("generated", "config.yaml"))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		notify, err := NewWatcher(paths, EmptyMatcher{}, logger.NewTestLogger(bytes.NewBuffer(nil)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		err = notify.Start()
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ReportMetric(float64(notify.(WatchDiagnostics).WatchCount()), "watches")
+		err = notify.Close()
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
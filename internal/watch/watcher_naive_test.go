@@ -3,13 +3,20 @@
 package watch
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tilt-dev/fsnotify"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
 func TestDontWatchEachFile(t *testing.T) {
@@ -113,3 +120,475 @@ func TestDontWatchEachFile(t *testing.T) {
 		t.Fatalf("watching more than 10 files: %d", n)
 	}
 }
+
+func TestFollowSymlinks(t *testing.T) {
+	if isRecursiveWatcher() {
+		t.Skip("Only watchers that walk the tree themselves follow symlinks")
+	}
+
+	setFollowSymlinks(t, true)
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	shared := f.TempDir("shared")
+	root := f.paths[0]
+	err := os.Symlink(shared, f.JoinPath(root, "pkg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.rebuildWatcher()
+	f.events = nil
+
+	f.WriteFile(f.JoinPath(shared, "a.txt"), "hello")
+	f.assertEvents(f.JoinPath(root, "pkg", "a.txt"))
+}
+
+func TestDontFollowSymlinksByDefault(t *testing.T) {
+	if isRecursiveWatcher() {
+		t.Skip("Only watchers that walk the tree themselves follow symlinks")
+	}
+
+	setFollowSymlinks(t, false)
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	shared := f.TempDir("shared")
+	root := f.paths[0]
+	err := os.Symlink(shared, f.JoinPath(root, "pkg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.rebuildWatcher()
+	f.events = nil
+
+	f.WriteFile(f.JoinPath(shared, "a.txt"), "hello")
+	f.assertEvents()
+}
+
+func TestFollowSymlinksCycle(t *testing.T) {
+	if isRecursiveWatcher() {
+		t.Skip("Only watchers that walk the tree themselves follow symlinks")
+	}
+
+	setFollowSymlinks(t, true)
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.paths[0]
+	a := f.JoinPath(root, "a")
+	f.MkdirAll(a)
+	err := os.Symlink(root, f.JoinPath(a, "loop"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.rebuildWatcher()
+	f.events = nil
+
+	// root and root/a, but nothing behind the loop
+	if n := numberOfWatches.Value(); n != 2 {
+		t.Fatalf("expected 2 watches, got %d", n)
+	}
+
+	path := f.JoinPath(a, "b.txt")
+	f.WriteFile(path, "hello")
+	f.assertEvents(path)
+}
+
+func setFollowSymlinks(t *testing.T, follow bool) {
+	orig := os.Getenv(FollowSymlinksEnvVar)
+	t.Cleanup(func() { os.Setenv(FollowSymlinksEnvVar, orig) })
+	os.Setenv(FollowSymlinksEnvVar, strconv.FormatBool(follow))
+}
+
+func TestRenameWithinWatchedDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("This test relies on inotify rename semantics")
+	}
+
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.paths[0]
+	oldPath := f.WriteFile(f.JoinPath(root, "old.txt"), "hello")
+	f.fsync()
+	f.events = nil
+
+	newPath := f.JoinPath(root, "new.txt")
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// The old path is reported first (it no longer exists), then the new one.
+	f.assertEvents(oldPath, newPath)
+}
+
+func TestCreatedDirEventsAreSorted(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("This test relies on inotify rename semantics")
+	}
+
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	// Build the tree outside the watched dir, then move it in all at once,
+	// so that we only learn about its contents by walking it.
+	staging := f.TempDir("staging")
+	for _, p := range []string{"z.txt", "a.txt", "a/c.txt", "b.txt"} {
+		f.WriteFile(filepath.Join(staging, "dir", p), "hello")
+	}
+	f.fsync()
+	f.events = nil
+
+	dir := f.JoinPath(f.paths[0], "dir")
+	if err := os.Rename(filepath.Join(staging, "dir"), dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sorted by path, not in the order we walked them (which would visit a/c.txt before a.txt).
+	f.assertEvents(
+		dir,
+		filepath.Join(dir, "a"),
+		filepath.Join(dir, "a.txt"),
+		filepath.Join(dir, "a", "c.txt"),
+		filepath.Join(dir, "b.txt"),
+		filepath.Join(dir, "z.txt"),
+	)
+}
+
+func TestRenameDirOutOfTree(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("This test relies on inotify rename semantics")
+	}
+
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.paths[0]
+	sub := f.JoinPath(root, "sub")
+	f.WriteFile(f.JoinPath(sub, "a.txt"), "hello")
+	f.rebuildWatcher()
+	f.events = nil
+
+	if n := numberOfWatches.Value(); n != 2 {
+		t.Fatalf("expected 2 watches, got %d", n)
+	}
+
+	dest := f.JoinPath(f.TempDir("outside"), "sub")
+	if err := os.Rename(sub, dest); err != nil {
+		t.Fatal(err)
+	}
+	f.assertEvents(sub)
+	f.events = nil
+
+	// Changes in the moved directory shouldn't show up under the old path.
+	f.WriteFile(f.JoinPath(dest, "b.txt"), "hello")
+	if err := os.Remove(f.JoinPath(dest, "a.txt")); err != nil {
+		t.Fatal(err)
+	}
+	f.assertEvents()
+
+	if n := numberOfWatches.Value(); n != 1 {
+		t.Fatalf("expected 1 watch, got %d", n)
+	}
+	if paths := f.notify.(WatchDiagnostics).WatchedPaths(); len(paths) != 1 || paths[0] != root {
+		t.Fatalf("expected only %s to be watched, got %v", root, paths)
+	}
+}
+
+func TestWatchFilesDoesNotWatchSiblingDirs(t *testing.T) {
+	if isRecursiveWatcher() {
+		t.Skip("Only watchers that walk the tree themselves add watches per directory")
+	}
+
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.TempDir("root")
+	a := f.JoinPath(root, "a")
+	config := f.JoinPath(a, "config.yaml")
+	extra := f.JoinPath(a, "extra.yaml")
+	settings := f.JoinPath(root, "b", "c", "settings.yaml")
+	f.WriteFile(config, "hello")
+	f.WriteFile(extra, "hello")
+	f.WriteFile(settings, "hello")
+	f.MkdirAll(f.JoinPath(a, "sibling", "nested"))
+
+	f.paths = append(f.paths, config, extra, settings)
+	f.rebuildWatcher()
+	f.events = nil
+
+	// watched, root/a, and root/b/c
+	if n := numberOfWatches.Value(); n != 3 {
+		t.Fatalf("expected 3 watches, got %d", n)
+	}
+
+	f.MkdirAll(f.JoinPath(a, "created"))
+	f.WriteFile(f.JoinPath(a, "created", "x.txt"), "hello")
+	f.WriteFile(f.JoinPath(a, "sibling.txt"), "hello")
+	f.WriteFile(config, "goodbye")
+	f.assertEvents(config)
+
+	if n := numberOfWatches.Value(); n != 3 {
+		t.Fatalf("expected 3 watches, got %d", n)
+	}
+}
+
+func TestWatchNonExistentPathOnlyWatchesAncestors(t *testing.T) {
+	if isRecursiveWatcher() {
+		t.Skip("Only watchers that walk the tree themselves add watches per directory")
+	}
+
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.TempDir("root")
+	for i := 0; i < 5; i++ {
+		f.MkdirAll(f.JoinPath(root, fmt.Sprintf("sibling%d", i), "nested"))
+	}
+	target := f.JoinPath(root, "x", "y")
+	f.watch(target)
+	f.events = nil
+
+	// watched and root
+	if n := numberOfWatches.Value(); n != 2 {
+		t.Fatalf("expected 2 watches, got %d", n)
+	}
+
+	file := f.JoinPath(target, "z.txt")
+	f.WriteFile(file, "hello")
+	f.assertEvents(file)
+}
+
+func TestMaxDepth(t *testing.T) {
+	if isRecursiveWatcher() {
+		t.Skip("Only watchers that walk the tree themselves limit depth")
+	}
+
+	setMaxDepth(t, "2")
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.paths[0]
+	a := f.JoinPath(root, "a")
+	b := f.JoinPath(a, "b")
+	c := f.JoinPath(b, "c")
+	f.MkdirAll(c)
+	f.rebuildWatcher()
+	f.events = nil
+
+	// root, root/a, and root/a/b, but not root/a/b/c
+	if n := numberOfWatches.Value(); n != 3 {
+		t.Fatalf("expected 3 watches, got %d", n)
+	}
+
+	f.WriteFile(f.JoinPath(c, "deep.txt"), "hello")
+	shallow := f.JoinPath(b, "shallow.txt")
+	f.WriteFile(shallow, "hello")
+	f.assertEvents(shallow)
+}
+
+func TestMaxDepthCreatedDir(t *testing.T) {
+	if isRecursiveWatcher() {
+		t.Skip("Only watchers that walk the tree themselves limit depth")
+	}
+
+	setMaxDepth(t, "1")
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.paths[0]
+	a := f.JoinPath(root, "a")
+	b := f.JoinPath(a, "b")
+	f.MkdirAll(b)
+	f.assertEvents(a, b)
+	f.events = nil
+
+	// root and root/a
+	if n := numberOfWatches.Value(); n != 2 {
+		t.Fatalf("expected 2 watches, got %d", n)
+	}
+
+	f.WriteFile(f.JoinPath(b, "deep.txt"), "hello")
+	f.assertEvents()
+}
+
+func setMaxDepth(t *testing.T, depth string) {
+	orig := os.Getenv(MaxDepthEnvVar)
+	t.Cleanup(func() { os.Setenv(MaxDepthEnvVar, orig) })
+	os.Setenv(MaxDepthEnvVar, depth)
+}
+
+func TestMaxDepthEnvVar(t *testing.T) {
+	for _, tc := range []struct {
+		env      string
+		expected int
+	}{
+		{"", 0},
+		{"a", 0},
+		{"-1", 0},
+		{"3", 3},
+	} {
+		setMaxDepth(t, tc.env)
+		if actual := DesiredMaxDepth(); actual != tc.expected {
+			t.Errorf("%s=%q: expected %d, got %d", MaxDepthEnvVar, tc.env, tc.expected, actual)
+		}
+	}
+}
+
+func TestStaticDirs(t *testing.T) {
+	if isRecursiveWatcher() {
+		t.Skip("Only watchers that walk the tree themselves add watches for new directories")
+	}
+
+	setStaticDirs(t, true)
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.paths[0]
+	existing := f.JoinPath(root, "existing")
+	f.MkdirAll(existing)
+	f.rebuildWatcher()
+	f.events = nil
+
+	created := f.JoinPath(root, "created")
+	f.MkdirAll(created)
+	f.assertEvents(created)
+	f.events = nil
+
+	// root and root/existing
+	if n := numberOfWatches.Value(); n != 2 {
+		t.Fatalf("expected 2 watches, got %d", n)
+	}
+
+	f.WriteFile(f.JoinPath(created, "a.txt"), "hello")
+	b := f.JoinPath(existing, "b.txt")
+	f.WriteFile(b, "hello")
+	f.assertEvents(b)
+}
+
+func TestStaticDirsWatchesNotifyPathsCreatedLater(t *testing.T) {
+	if isRecursiveWatcher() {
+		t.Skip("Only watchers that walk the tree themselves add watches for new directories")
+	}
+
+	setStaticDirs(t, true)
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	root := f.JoinPath("root")
+	f.MkdirAll(root)
+	watched := f.JoinPath("root", "parent", "watched")
+	f.watch(watched)
+	f.fsync()
+	f.events = nil
+
+	f.MkdirAll(watched)
+	f.fsync()
+	f.events = nil
+
+	a := f.JoinPath(watched, "a.txt")
+	f.WriteFile(a, "hello")
+	f.assertEvents(a)
+}
+
+func TestWatchCreatedDirsByDefault(t *testing.T) {
+	setStaticDirs(t, false)
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	created := f.JoinPath(f.paths[0], "created")
+	f.MkdirAll(created)
+	f.assertEvents(created)
+	f.events = nil
+
+	a := f.JoinPath(created, "a.txt")
+	f.WriteFile(a, "hello")
+	f.assertEvents(a)
+}
+
+func setStaticDirs(t *testing.T, static bool) {
+	orig := os.Getenv(StaticDirsEnvVar)
+	t.Cleanup(func() { os.Setenv(StaticDirsEnvVar, orig) })
+	os.Setenv(StaticDirsEnvVar, strconv.FormatBool(static))
+}
+
+func TestSkipUnchangedContent(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("This test relies on inotify write semantics")
+	}
+
+	setSkipUnchanged(t, true)
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	path := f.WriteFile(f.JoinPath(f.paths[0], "a.txt"), "hello")
+	f.assertEvents(path)
+	f.events = nil
+
+	// Rewrite the same bytes in place, so there's exactly one write event.
+	overwrite(t, path, "hello")
+	f.assertEvents()
+
+	overwrite(t, path, "jello")
+	f.assertEvents(path)
+}
+
+func TestReportUnchangedContentByDefault(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("This test relies on inotify write semantics")
+	}
+
+	setSkipUnchanged(t, false)
+	f := newNotifyFixture(t)
+	defer f.tearDown()
+
+	path := f.WriteFile(f.JoinPath(f.paths[0], "a.txt"), "hello")
+	f.assertEvents(path)
+	f.events = nil
+
+	overwrite(t, path, "hello")
+	f.assertEvents(path)
+}
+
+func TestOverflowIsCountedAndWarnedOnce(t *testing.T) {
+	defer numberOfOverflows.Set(0)
+	numberOfOverflows.Set(0)
+
+	out := bytes.NewBuffer(nil)
+	d := &naiveNotify{
+		log:    logger.NewTestLogger(out),
+		errors: make(chan error),
+	}
+	errs := make(chan error)
+	go d.errorLoop(errs)
+
+	otherErr := fmt.Errorf("something else")
+	for _, err := range []error{fsnotify.ErrEventOverflow, otherErr, fsnotify.ErrEventOverflow} {
+		errs <- err
+		assert.Equal(t, err, <-d.errors, "errors should be passed through")
+	}
+	close(errs)
+	_, ok := <-d.errors
+	assert.False(t, ok, "errors should be closed")
+
+	assert.Equal(t, int64(2), numberOfOverflows.Value())
+	assert.Equal(t, 1, strings.Count(out.String(), "the OS dropped file events"),
+		"overflow warnings should be throttled. Output:\n%s", out.String())
+}
+
+func overwrite(t *testing.T, path, contents string) {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(contents); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func setSkipUnchanged(t *testing.T, skip bool) {
+	orig := os.Getenv(SkipUnchangedEnvVar)
+	t.Cleanup(func() { os.Setenv(SkipUnchangedEnvVar, orig) })
+	os.Setenv(SkipUnchangedEnvVar, strconv.FormatBool(skip))
+}
@@ -0,0 +1,278 @@
+package watch
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// A file watcher that stats all the watched paths on an interval.
+//
+// Used when OS file events are unreliable (e.g., NFS-mounted source trees
+// or some container volumes), where fsnotify can silently miss changes.
+// Much more expensive than the event-based watchers, so we only use it for
+// paths on mounts where OS file events don't work, or when TILT_WATCH_POLL=1.
+type pollNotify struct {
+	// Paths that we're watching that should be passed up to the caller.
+	notifyList map[string]bool
+
+	// Guards ignore, which SetIgnore can swap while we're polling.
+	ignoreMu sync.RWMutex
+	ignore   PathMatcher
+
+	log      logger.Logger
+	interval time.Duration
+
+	// Paths we watch even if the ignores would skip them.
+	forceInclude forceIncludeList
+
+	events chan FileEvent
+	errors chan error
+	stop   chan struct{}
+
+	// Guards started and closed, so that Close is safe to call more than
+	// once, or without Start.
+	mu      sync.Mutex
+	started bool
+	closed  bool
+
+	// The last state we saw for every file under a watched path.
+	files map[string]pollFileState
+}
+
+type pollFileState struct {
+	modTime time.Time
+	size    int64
+}
+
+func newPollWatcher(paths []string, ignore PathMatcher, l logger.Logger, interval time.Duration) (*pollNotify, error) {
+	if ignore == nil {
+		return nil, errors.New("newPollWatcher: ignore is nil")
+	}
+
+	notifyList := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "newPollWatcher")
+		}
+		notifyList[path] = true
+	}
+
+	return &pollNotify{
+		notifyList:   notifyList,
+		ignore:       ignore,
+		log:          l,
+		interval:     interval,
+		forceInclude: DesiredForceIncludePaths(),
+		events:       make(chan FileEvent),
+		errors:       make(chan error),
+		stop:         make(chan struct{}),
+	}, nil
+}
+
+func (d *pollNotify) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return errors.New("pollNotify: already closed")
+	}
+	d.started = true
+	numberOfWatches.Add(int64(len(d.notifyList)))
+
+	// Take an initial snapshot, so that we only report changes
+	// that happen after we start watching.
+	d.files = d.scan()
+
+	go d.loop()
+	return nil
+}
+
+func (d *pollNotify) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.closed {
+		return nil
+	}
+	d.closed = true
+	if d.started {
+		numberOfWatches.Add(int64(-len(d.notifyList)))
+	}
+	close(d.stop)
+	return nil
+}
+
+func (d *pollNotify) Events() chan FileEvent {
+	return d.events
+}
+
+func (d *pollNotify) Errors() chan error {
+	return d.errors
+}
+
+func (d *pollNotify) WatchedPaths() []string {
+	result := make([]string, 0, len(d.notifyList))
+	for path := range d.notifyList {
+		result = append(result, path)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// The polling watcher doesn't use any OS watches.
+func (d *pollNotify) WatchCount() int64 {
+	return 0
+}
+
+// Swaps the ignore rules while we're polling. Files that the old rules
+// ignored are reported as changed on the next poll.
+func (d *pollNotify) SetIgnore(ignore PathMatcher) {
+	d.ignoreMu.Lock()
+	defer d.ignoreMu.Unlock()
+	d.ignore = ignore
+}
+
+func (d *pollNotify) getIgnore() PathMatcher {
+	d.ignoreMu.RLock()
+	defer d.ignoreMu.RUnlock()
+	return d.ignore
+}
+
+func (d *pollNotify) loop() {
+	defer close(d.events)
+	defer close(d.errors)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+
+		current := d.scan()
+		for _, path := range changedPollPaths(d.files, current) {
+			select {
+			case <-d.stop:
+				return
+			case d.events <- NewFileEvent(path):
+			}
+		}
+		d.files = current
+	}
+}
+
+// Returns the paths that were created, modified, or deleted between two scans, sorted.
+func changedPollPaths(previous, current map[string]pollFileState) []string {
+	var changed []string
+	for path, state := range current {
+		prevState, ok := previous[path]
+		if !ok || prevState != state {
+			changed = append(changed, path)
+		}
+	}
+	for path := range previous {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// Stat every file under the watched paths that we'd notify the caller about.
+func (d *pollNotify) scan() map[string]pollFileState {
+	result := make(map[string]pollFileState)
+	for root := range d.notifyList {
+		err := filepath.WalkDir(root, func(path string, info fs.DirEntry, err error) error {
+			if err != nil {
+				// Files can disappear while we're walking, which is fine.
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+
+			if info.IsDir() {
+				shouldSkipDir, err := d.shouldSkipDir(path)
+				if err != nil {
+					return err
+				}
+				if shouldSkipDir {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			if !d.shouldNotify(path) {
+				return nil
+			}
+
+			fi, err := info.Info()
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			result[path] = pollFileState{modTime: fi.ModTime(), size: fi.Size()}
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			d.log.Infof("Error polling path %s: %v", root, err)
+		}
+	}
+	return result
+}
+
+func (d *pollNotify) shouldNotify(path string) bool {
+	if !d.forceInclude.includes(path) {
+		ignore, err := d.getIgnore().Matches(path)
+		if err != nil {
+			d.log.Infof("Error matching path %q: %v", path, err)
+		} else if ignore {
+			return false
+		}
+	}
+
+	if d.notifyList[path] {
+		return true
+	}
+	for root := range d.notifyList {
+		if ospath.IsChild(root, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *pollNotify) shouldSkipDir(path string) (bool, error) {
+	// If path is directly in the notifyList, we should always watch it.
+	if d.notifyList[path] {
+		return false, nil
+	}
+
+	if d.forceInclude.mustWalk(path) {
+		return false, nil
+	}
+
+	skip, err := d.getIgnore().MatchesEntireDir(path)
+	if err != nil {
+		return false, errors.Wrap(err, "shouldSkipDir")
+	}
+	return skip, nil
+}
+
+var _ Notify = &pollNotify{}
+var _ WatchDiagnostics = &pollNotify{}
+var _ IgnoreSetter = &pollNotify{}
@@ -0,0 +1,193 @@
+package watch
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/dockerignore"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestPollIntervalEnvVar(t *testing.T) {
+	orig := os.Getenv(PollIntervalEnvVar)
+	defer os.Setenv(PollIntervalEnvVar, orig)
+
+	os.Setenv(PollIntervalEnvVar, "")
+	assert.Equal(t, defaultPollInterval, DesiredPollInterval())
+
+	os.Setenv(PollIntervalEnvVar, "a")
+	assert.Equal(t, defaultPollInterval, DesiredPollInterval())
+
+	os.Setenv(PollIntervalEnvVar, "-1s")
+	assert.Equal(t, defaultPollInterval, DesiredPollInterval())
+
+	os.Setenv(PollIntervalEnvVar, "250ms")
+	assert.Equal(t, 250*time.Millisecond, DesiredPollInterval())
+}
+
+func TestShouldPoll(t *testing.T) {
+	orig := os.Getenv(PollEnvVar)
+	defer os.Setenv(PollEnvVar, orig)
+
+	os.Setenv(PollEnvVar, "")
+	assert.False(t, ShouldPoll())
+
+	os.Setenv(PollEnvVar, "nope")
+	assert.False(t, ShouldPoll())
+
+	os.Setenv(PollEnvVar, "1")
+	assert.True(t, ShouldPoll())
+}
+
+func TestShouldPollByMount(t *testing.T) {
+	orig := os.Getenv(PollEnvVar)
+	defer os.Setenv(PollEnvVar, orig)
+
+	os.Setenv(PollEnvVar, "")
+	assert.True(t, ShouldPollByMount())
+
+	os.Setenv(PollEnvVar, "1")
+	assert.True(t, ShouldPollByMount())
+
+	os.Setenv(PollEnvVar, "0")
+	assert.False(t, ShouldPollByMount())
+}
+
+func TestPollCloseTwice(t *testing.T) {
+	f := newPollFixture(t)
+	defer f.tearDown()
+
+	f.start(f.Path())
+	assert.Equal(t, int64(1), numberOfWatches.Value())
+
+	require.NoError(t, f.notify.Close())
+	require.NoError(t, f.notify.Close())
+	assert.Equal(t, int64(0), numberOfWatches.Value())
+}
+
+func TestPollCloseWithoutStart(t *testing.T) {
+	f := newPollFixture(t)
+	defer f.tearDown()
+
+	notify, err := newPollWatcher([]string{f.Path()}, f.ignore, logger.NewTestLogger(bytes.NewBuffer(nil)), 10*time.Millisecond)
+	require.NoError(t, err)
+	require.NoError(t, notify.Close())
+	assert.Equal(t, int64(0), numberOfWatches.Value())
+	assert.Error(t, notify.Start())
+}
+
+func TestPollNoInitialEvents(t *testing.T) {
+	f := newPollFixture(t)
+	defer f.tearDown()
+
+	f.WriteFile("a.txt", "hello")
+	f.start(f.Path())
+
+	f.assertNoEvents()
+}
+
+func TestPollCreateModifyDelete(t *testing.T) {
+	f := newPollFixture(t)
+	defer f.tearDown()
+
+	f.WriteFile("a.txt", "hello")
+	f.start(f.Path())
+
+	path := f.WriteFile("b/c.txt", "hello")
+	f.assertNextEvent(path)
+
+	f.WriteFile("b/c.txt", "hello world")
+	f.assertNextEvent(path)
+
+	f.Rm("b/c.txt")
+	f.assertNextEvent(path)
+}
+
+func TestPollIgnore(t *testing.T) {
+	f := newPollFixture(t)
+	defer f.tearDown()
+
+	ignore, err := dockerignore.NewDockerPatternMatcher(f.Path(), []string{"ignored"})
+	require.NoError(t, err)
+	f.ignore = ignore
+	f.start(f.Path())
+
+	f.WriteFile("ignored/a.txt", "hello")
+	path := f.WriteFile("b.txt", "hello")
+	f.assertNextEvent(path)
+	f.assertNoEvents()
+}
+
+func TestPollWatchedFile(t *testing.T) {
+	f := newPollFixture(t)
+	defer f.tearDown()
+
+	path := f.JoinPath("a.txt")
+	f.start(path)
+
+	f.WriteFile("sibling.txt", "hello")
+	f.WriteFile("a.txt", "hello")
+	f.assertNextEvent(path)
+	f.assertNoEvents()
+}
+
+func TestPollWatchDiagnostics(t *testing.T) {
+	f := newPollFixture(t)
+	defer f.tearDown()
+
+	f.start(f.JoinPath("b"), f.JoinPath("a"))
+
+	assert.Equal(t, []string{f.JoinPath("a"), f.JoinPath("b")}, f.notify.WatchedPaths())
+	assert.Equal(t, int64(0), f.notify.WatchCount())
+}
+
+type pollFixture struct {
+	*tempdir.TempDirFixture
+	ignore PathMatcher
+	notify *pollNotify
+}
+
+func newPollFixture(t *testing.T) *pollFixture {
+	return &pollFixture{
+		TempDirFixture: tempdir.NewTempDirFixture(t),
+		ignore:         EmptyMatcher{},
+	}
+}
+
+func (f *pollFixture) start(paths ...string) {
+	notify, err := newPollWatcher(paths, f.ignore, logger.NewTestLogger(bytes.NewBuffer(nil)), 10*time.Millisecond)
+	require.NoError(f.T(), err)
+	require.NoError(f.T(), notify.Start())
+	f.notify = notify
+}
+
+func (f *pollFixture) assertNextEvent(path string) {
+	select {
+	case e := <-f.notify.Events():
+		assert.Equal(f.T(), path, e.Path())
+	case <-time.After(time.Second):
+		f.T().Fatalf("timed out waiting for event on %s", path)
+	}
+}
+
+func (f *pollFixture) assertNoEvents() {
+	select {
+	case e := <-f.notify.Events():
+		f.T().Fatalf("unexpected event: %v", e)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func (f *pollFixture) tearDown() {
+	if f.notify != nil {
+		_ = f.notify.Close()
+	}
+	f.TempDirFixture.TearDown()
+	numberOfWatches.Set(0)
+}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"context"
+	"regexp"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -63,12 +64,16 @@ type FileWatchSpec struct {
 type IgnoreDef struct {
 	// BasePath is the base path for the patterns. It cannot be empty.
 	//
-	// If no patterns are specified, everything under it will be recursively ignored.
+	// If no patterns or regexes are specified, everything under it will be recursively ignored.
 	BasePath string `json:"basePath" protobuf:"bytes,1,opt,name=basePath"`
 	// Patterns are dockerignore style rules. Absolute-style patterns will be rooted to the BasePath.
 	//
 	// See https://docs.docker.com/engine/reference/builder/#dockerignore-file.
 	Patterns []string `json:"patterns,omitempty" protobuf:"bytes,2,rep,name=patterns"`
+	// Regexes are Go regular expressions (see https://golang.org/pkg/regexp/syntax/).
+	// A path is ignored if any regex matches its path relative to the BasePath,
+	// using forward slashes as separators.
+	Regexes []string `json:"regexes,omitempty" protobuf:"bytes,3,rep,name=regexes"`
 }
 
 var _ resource.Object = &FileWatch{}
@@ -118,6 +123,15 @@ func (in *FileWatch) Validate(_ context.Context) field.ErrorList {
 			field.NewPath("spec", "watchedPaths"),
 			"cannot be an empty list"))
 	}
+	for i, ignore := range in.Spec.Ignores {
+		for j, r := range ignore.Regexes {
+			if _, err := regexp.Compile(r); err != nil {
+				fieldErrors = append(fieldErrors, field.Invalid(
+					field.NewPath("spec", "ignores").Index(i).Child("regexes").Index(j),
+					r, err.Error()))
+			}
+		}
+	}
 	return fieldErrors
 }
 
@@ -252,6 +252,10 @@ type Pod struct {
 	InitContainers []Container `json:"initContainers,omitempty" protobuf:"bytes,7,rep,name=initContainers"`
 	// Containers are the containers belonging to the Pod.
 	Containers []Container `json:"containers" protobuf:"bytes,8,rep,name=containers"`
+	// EphemeralContainers are containers added to the running Pod (e.g., with `kubectl debug`).
+	//
+	// +optional
+	EphemeralContainers []Container `json:"ephemeralContainers,omitempty" protobuf:"bytes,16,rep,name=ephemeralContainers"`
 
 	// AncestorUID is the UID from the WatchRef that matched this Pod.
 	//
@@ -174,6 +174,11 @@ type UIResourceStatus struct {
 	//
 	// +optional
 	Order int32 `json:"order,omitempty" protobuf:"varint,15,opt,name=order"`
+
+	// True if the user paused live updates for this resource. File changes
+	// that would be live-updated are skipped until they resume.
+	// +optional
+	LiveUpdatePaused bool `json:"liveUpdatePaused,omitempty" protobuf:"varint,16,opt,name=liveUpdatePaused"`
 }
 
 // UIResource implements ObjectWithStatusSubResource interface.
@@ -276,6 +281,12 @@ type UIBuildTerminated struct {
 	// build+deploy to reset the pod state to what's on disk.
 	// +optional
 	IsCrashRebuild bool `json:"isCrashRebuild,omitempty" protobuf:"varint,6,opt,name=isCrashRebuild"`
+
+	// If a live update fell back to this full build, why.
+	//
+	// One of: stop-path, no-match, exec-error, limit.
+	// +optional
+	FallBackReason string `json:"fallBackReason,omitempty" protobuf:"bytes,7,opt,name=fallBackReason"`
 }
 
 // UIResourceKubernetes contains status information specific to Kubernetes.
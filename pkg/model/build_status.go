@@ -14,6 +14,23 @@ const BuildTypeDockerCompose BuildType = "docker-compose"
 const BuildTypeK8s BuildType = "k8s"
 const BuildTypeLocal BuildType = "local"
 
+// Why a Live Update fell back to a full build.
+type FallBackReason string
+
+const FallBackReasonNone FallBackReason = ""
+
+// A file matching a fall_back_on() step changed.
+const FallBackReasonStopPath FallBackReason = "stop-path"
+
+// A file changed that didn't match any sync() step.
+const FallBackReasonNoMatch FallBackReason = "no-match"
+
+// Copying files to (or running steps in) the container failed unexpectedly.
+const FallBackReasonExecError FallBackReason = "exec-error"
+
+// The update was bigger than live_update_max_files or live_update_max_bytes allow.
+const FallBackReasonLimit FallBackReason = "limit"
+
 type BuildRecord struct {
 	Edits      []string
 	Error      error
@@ -23,6 +40,9 @@ type BuildRecord struct {
 
 	BuildTypes []BuildType
 
+	// If a Live Update fell back to a full build, why.
+	FallBackReason FallBackReason
+
 	// The lookup key for the logs in the logstore.
 	SpanID LogSpanID
 
@@ -1,6 +1,10 @@
 package model
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
+
 	"github.com/pkg/errors"
 )
 
@@ -12,6 +16,36 @@ import (
 type LiveUpdate struct {
 	Steps   []LiveUpdateStep
 	BaseDir string // directory where the LiveUpdate was initialized (we'll use this to eval. any relative paths)
+
+	// How to copy files into the container. Overrides the session's update mode.
+	Updater LiveUpdateUpdater
+
+	// If non-empty, only update the container with this name, even if other
+	// containers in the pod run the same image (e.g., a sidecar).
+	ContainerName string
+}
+
+type LiveUpdateUpdater string
+
+const (
+	// Let the session's update mode decide.
+	LiveUpdateUpdaterAuto LiveUpdateUpdater = ""
+
+	// Talk to the container runtime directly (e.g., when the cluster
+	// shares the local Docker daemon).
+	LiveUpdateUpdaterDocker LiveUpdateUpdater = "docker"
+
+	// Use kubectl exec.
+	LiveUpdateUpdaterExec LiveUpdateUpdater = "exec"
+)
+
+func ParseLiveUpdateUpdater(s string) (LiveUpdateUpdater, error) {
+	switch u := LiveUpdateUpdater(s); u {
+	case LiveUpdateUpdaterAuto, LiveUpdateUpdaterDocker, LiveUpdateUpdaterExec:
+		return u, nil
+	}
+	return "", fmt.Errorf("unknown live update updater %q. Valid values: %q, %q",
+		s, LiveUpdateUpdaterDocker, LiveUpdateUpdaterExec)
 }
 
 func NewLiveUpdate(steps []LiveUpdateStep, baseDir string) (LiveUpdate, error) {
@@ -35,9 +69,15 @@ func NewLiveUpdate(steps []LiveUpdateStep, baseDir string) (LiveUpdate, error) {
 	}
 
 	seenRunStep := false
+	seenSyncStep := false
 	for i, step := range steps {
 		switch step.(type) {
+		case LiveUpdateLocalPreSyncStep:
+			if seenSyncStep || seenRunStep {
+				return LiveUpdate{}, errors.New("all local_pre_sync steps must precede all sync and run steps")
+			}
 		case LiveUpdateSyncStep:
+			seenSyncStep = true
 			if seenRunStep {
 				return LiveUpdate{}, errors.New("all sync steps must precede all run steps")
 			}
@@ -47,6 +87,10 @@ func NewLiveUpdate(steps []LiveUpdateStep, baseDir string) (LiveUpdate, error) {
 			if i != len(steps)-1 {
 				return LiveUpdate{}, errors.New("restart container is only valid as the last step")
 			}
+		case LiveUpdateSignalProcessStep:
+			if i != len(steps)-1 {
+				return LiveUpdate{}, errors.New("signal process is only valid as the last step")
+			}
 		}
 	}
 	return LiveUpdate{Steps: steps, BaseDir: baseDir}, nil
@@ -65,13 +109,46 @@ type LiveUpdateFallBackOnStep struct {
 
 func (l LiveUpdateFallBackOnStep) liveUpdateStep() {}
 
+// Specifies that `Command` should be executed locally before we sync any files
+// (e.g., to compile assets that a sync step then copies into the container).
+// The files it writes under a sync source are synced in the same update. To
+// find them, each update checks the mod time of every file under the syncs.
+type LiveUpdateLocalPreSyncStep struct {
+	Command Cmd
+}
+
+func (l LiveUpdateLocalPreSyncStep) liveUpdateStep() {}
+
 // Specifies that changes to local path `Source` should be synced to container path `Dest`
 type LiveUpdateSyncStep struct {
 	Source, Dest string
+
+	// If true, loading the Tiltfile fails if `Source` doesn't exist, rather
+	// than warning (since it might be created later, e.g., by a build).
+	MustExist bool
 }
 
 func (l LiveUpdateSyncStep) liveUpdateStep() {}
 
+var windowsContainerPathRE = regexp.MustCompile(`^[a-zA-Z]:([/\\]|$)`)
+
+// IsWindowsContainerPath is true if p is an absolute path in a Windows
+// container (e.g., C:\app or C:/app). We treat every other container path
+// as a Linux path.
+func IsWindowsContainerPath(p string) bool {
+	return windowsContainerPathRE.MatchString(p)
+}
+
+// NormalizeContainerPath converts a Windows container path to forward slashes,
+// so that we can do all our container path math with the `path` package.
+// Linux container paths are unchanged.
+func NormalizeContainerPath(p string) string {
+	if !IsWindowsContainerPath(p) {
+		return p
+	}
+	return strings.ReplaceAll(p, `\`, "/")
+}
+
 func (l LiveUpdateSyncStep) toSync() Sync {
 	return Sync{
 		LocalPath:     l.Source,
@@ -85,12 +162,15 @@ func (l LiveUpdateSyncStep) toSync() Sync {
 type LiveUpdateRunStep struct {
 	Command  Cmd
 	Triggers PathSet
+
+	// If true, `Command` runs once per changed file, instead of once per update.
+	ForEachFile bool
 }
 
 func (l LiveUpdateRunStep) liveUpdateStep() {}
 
 func (l LiveUpdateRunStep) toRun() Run {
-	return Run{Cmd: l.Command, Triggers: l.Triggers}
+	return Run{Cmd: l.Command, Triggers: l.Triggers, ForEachFile: l.ForEachFile}
 }
 
 // Specifies that the container should be restarted when any files in `Sync` steps have changed.
@@ -98,8 +178,35 @@ type LiveUpdateRestartContainerStep struct{}
 
 func (l LiveUpdateRestartContainerStep) liveUpdateStep() {}
 
+// Specifies that, after any files in `Sync` steps have changed, we should send
+// `Signal` to the container's main process (PID 1) instead of restarting the
+// container, for apps that reload when they get a signal (e.g., SIGHUP).
+type LiveUpdateSignalProcessStep struct {
+	// The signal name, without the SIG prefix (e.g., "HUP").
+	Signal string
+}
+
+func (l LiveUpdateSignalProcessStep) liveUpdateStep() {}
+
+// Signals an app might catch to reload. KILL and STOP can't be caught, so
+// they'd stop the container's main process instead of reloading it.
+var signalNames = map[string]bool{
+	"HUP": true, "INT": true, "QUIT": true, "USR1": true,
+	"USR2": true, "TERM": true, "CONT": true, "WINCH": true,
+}
+
+// NormalizeSignal accepts a signal name with or without the SIG prefix
+// (e.g., "SIGHUP" or "hup") and returns it without the prefix ("HUP").
+func NormalizeSignal(s string) (string, error) {
+	name := strings.TrimPrefix(strings.ToUpper(s), "SIG")
+	if !signalNames[name] {
+		return "", fmt.Errorf("unsupported signal %q", s)
+	}
+	return name, nil
+}
+
 // FallBackOnFiles returns a PathSet of files which, if any have changed, indicate
-// that we should fall back to an image build.
+// that we should fall back to an image build. The paths may be globs (see PathSet.AnyGlobMatch).
 func (lu LiveUpdate) FallBackOnFiles() PathSet {
 	var files []string
 	for _, step := range lu.Steps {
@@ -111,6 +218,21 @@ func (lu LiveUpdate) FallBackOnFiles() PathSet {
 	return NewPathSet(files, lu.BaseDir)
 }
 
+// LocalPreSyncCmds returns the commands to run locally before syncing, in order.
+// Empty commands are skipped.
+func (lu LiveUpdate) LocalPreSyncCmds() []Cmd {
+	var cmds []Cmd
+	for _, step := range lu.Steps {
+		switch step := step.(type) {
+		case LiveUpdateLocalPreSyncStep:
+			if !step.Command.Empty() {
+				cmds = append(cmds, step.Command)
+			}
+		}
+	}
+	return cmds
+}
+
 func (lu LiveUpdate) SyncSteps() []Sync {
 	var syncs []Sync
 	for _, step := range lu.Steps {
@@ -133,6 +255,19 @@ func (lu LiveUpdate) RunSteps() []Run {
 	return runs
 }
 
+// SignalOnUpdate returns the signal to send to the container's main process
+// after an update, or "" if we shouldn't send one.
+func (lu LiveUpdate) SignalOnUpdate() string {
+	if len(lu.Steps) > 0 {
+		// The SignalProcess step, if present, must be the last step.
+		last := lu.Steps[len(lu.Steps)-1]
+		if step, ok := last.(LiveUpdateSignalProcessStep); ok {
+			return step.Signal
+		}
+	}
+	return ""
+}
+
 func (lu LiveUpdate) ShouldRestart() bool {
 	if len(lu.Steps) > 0 {
 		// Currently we require that the Restart step, if present, must be the last step.
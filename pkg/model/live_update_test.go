@@ -11,8 +11,8 @@ const BaseDir = "/base/directory"
 func TestNewLiveUpdate(t *testing.T) {
 	steps := []LiveUpdateStep{
 		LiveUpdateFallBackOnStep{[]string{"quu", "qux"}},
-		LiveUpdateSyncStep{"foo", "bar"},
-		LiveUpdateRunStep{Cmd{Argv: []string{"hello"}, Dir: BaseDir}, NewPathSet([]string{"goodbye"}, BaseDir)},
+		LiveUpdateSyncStep{Source: "foo", Dest: "bar"},
+		LiveUpdateRunStep{Command: Cmd{Argv: []string{"hello"}, Dir: BaseDir}, Triggers: NewPathSet([]string{"goodbye"}, BaseDir)},
 		LiveUpdateRestartContainerStep{},
 	}
 	lu, err := NewLiveUpdate(steps, BaseDir)
@@ -20,11 +20,11 @@ func TestNewLiveUpdate(t *testing.T) {
 		return
 	}
 
-	assert.Equal(t, LiveUpdate{steps, BaseDir}, lu)
+	assert.Equal(t, LiveUpdate{Steps: steps, BaseDir: BaseDir}, lu)
 }
 
 func TestNewLiveUpdateRestartContainerNotLast(t *testing.T) {
-	steps := []LiveUpdateStep{LiveUpdateRestartContainerStep{}, LiveUpdateSyncStep{"foo", "bar"}}
+	steps := []LiveUpdateStep{LiveUpdateRestartContainerStep{}, LiveUpdateSyncStep{Source: "foo", Dest: "bar"}}
 	_, err := NewLiveUpdate(steps, BaseDir)
 	if !assert.Error(t, err) {
 		return
@@ -32,8 +32,47 @@ func TestNewLiveUpdateRestartContainerNotLast(t *testing.T) {
 	assert.Contains(t, err.Error(), "restart container is only valid as the last step")
 }
 
+func TestNewLiveUpdateSignalProcessNotLast(t *testing.T) {
+	steps := []LiveUpdateStep{LiveUpdateSignalProcessStep{Signal: "HUP"}, LiveUpdateSyncStep{Source: "foo", Dest: "bar"}}
+	_, err := NewLiveUpdate(steps, BaseDir)
+	if !assert.Error(t, err) {
+		return
+	}
+	assert.Contains(t, err.Error(), "signal process is only valid as the last step")
+}
+
+func TestLiveUpdateSignalOnUpdate(t *testing.T) {
+	steps := []LiveUpdateStep{LiveUpdateSyncStep{Source: "foo", Dest: "bar"}, LiveUpdateSignalProcessStep{Signal: "USR1"}}
+	lu, err := NewLiveUpdate(steps, BaseDir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "USR1", lu.SignalOnUpdate())
+	assert.False(t, lu.ShouldRestart())
+
+	lu, err = NewLiveUpdate(steps[:1], BaseDir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "", lu.SignalOnUpdate())
+}
+
+func TestNormalizeSignal(t *testing.T) {
+	for _, s := range []string{"HUP", "SIGHUP", "sighup", "hup"} {
+		sig, err := NormalizeSignal(s)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, "HUP", sig)
+		}
+	}
+
+	for _, s := range []string{"SIGFOO", "SIGKILL", "STOP"} {
+		_, err := NormalizeSignal(s)
+		assert.Error(t, err, s)
+	}
+}
+
 func TestNewLiveUpdateSyncAfterRun(t *testing.T) {
-	steps := []LiveUpdateStep{LiveUpdateRunStep{}, LiveUpdateSyncStep{"foo", "bar"}}
+	steps := []LiveUpdateStep{LiveUpdateRunStep{}, LiveUpdateSyncStep{Source: "foo", Dest: "bar"}}
 	_, err := NewLiveUpdate(steps, BaseDir)
 	if !assert.Error(t, err) {
 		return
@@ -41,12 +80,35 @@ func TestNewLiveUpdateSyncAfterRun(t *testing.T) {
 	assert.Contains(t, err.Error(), "all sync steps must precede all run steps")
 }
 
+func TestNewLiveUpdateLocalPreSyncAfterSync(t *testing.T) {
+	steps := []LiveUpdateStep{LiveUpdateSyncStep{Source: "foo", Dest: "bar"}, LiveUpdateLocalPreSyncStep{ToUnixCmd("make")}}
+	_, err := NewLiveUpdate(steps, BaseDir)
+	if !assert.Error(t, err) {
+		return
+	}
+	assert.Contains(t, err.Error(), "all local_pre_sync steps must precede all sync and run steps")
+}
+
+func TestNewLiveUpdateLocalPreSyncCmds(t *testing.T) {
+	steps := []LiveUpdateStep{
+		LiveUpdateLocalPreSyncStep{ToUnixCmd("make assets")},
+		LiveUpdateLocalPreSyncStep{},
+		LiveUpdateLocalPreSyncStep{ToUnixCmd("make bundle")},
+		LiveUpdateSyncStep{Source: "foo", Dest: "bar"},
+	}
+	lu, err := NewLiveUpdate(steps, BaseDir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []Cmd{ToUnixCmd("make assets"), ToUnixCmd("make bundle")}, lu.LocalPreSyncCmds())
+}
+
 func TestNewLiveUpdateFallBackOnStepsNotFirst(t *testing.T) {
 	steps := []LiveUpdateStep{
 		LiveUpdateFallBackOnStep{[]string{"a"}},
-		LiveUpdateSyncStep{"foo", "bar"},
+		LiveUpdateSyncStep{Source: "foo", Dest: "bar"},
 		LiveUpdateFallBackOnStep{[]string{"b", "c"}},
-		LiveUpdateSyncStep{"baz", "qux"},
+		LiveUpdateSyncStep{Source: "baz", Dest: "qux"},
 	}
 	_, err := NewLiveUpdate(steps, BaseDir)
 	if !assert.Error(t, err) {
@@ -68,3 +130,24 @@ func TestNewLiveUpdateFallBackOnFiles(t *testing.T) {
 	expectedFallBackFiles := NewPathSet([]string{"a", "b", "c", "d"}, BaseDir)
 	assert.Equal(t, expectedFallBackFiles, lu.FallBackOnFiles())
 }
+
+func TestIsWindowsContainerPath(t *testing.T) {
+	for _, tc := range []struct {
+		path       string
+		isWindows  bool
+		normalized string
+	}{
+		{"/app", false, "/app"},
+		{"/app/C:/foo", false, "/app/C:/foo"},
+		{"app", false, "app"},
+		{`C:\app\src`, true, "C:/app/src"},
+		{"c:/app", true, "c:/app"},
+		{"C:", true, "C:"},
+		{"C:app", false, "C:app"},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.isWindows, IsWindowsContainerPath(tc.path))
+			assert.Equal(t, tc.normalized, NormalizeContainerPath(tc.path))
+		})
+	}
+}
@@ -310,6 +310,9 @@ type Run struct {
 	// Optional. If not specified, this command runs on every change.
 	// If specified, we only run the Cmd if the changed file matches a trigger.
 	Triggers PathSet
+	// Optional. If true, we run the Cmd once per changed file (that matches
+	// a trigger, if there are any), instead of once per update.
+	ForEachFile bool
 }
 
 func (r Run) WithTriggers(paths []string, baseDir string) Run {
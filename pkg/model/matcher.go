@@ -2,8 +2,10 @@ package model
 
 import (
 	"path/filepath"
+	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/tilt-dev/dockerignore"
 
 	"github.com/tilt-dev/tilt/internal/ospath"
 )
@@ -94,6 +96,48 @@ func NewRelativeFileOrChildMatcher(baseDir string, paths ...string) fileOrChildM
 	return fileOrChildMatcher{paths: pathMap}
 }
 
+// This matcher supports dockerignore-style patterns: `*`, `**`, and `!` exclusions,
+// where the last matching pattern wins. Like fileOrChildMatcher, a pattern also
+// matches everything under the paths it matches.
+type globMatcher struct {
+	matcher *dockerignore.PatternMatcher
+}
+
+func (m globMatcher) Matches(f string) (bool, error) {
+	return m.matcher.Matches(f)
+}
+
+func (m globMatcher) MatchesEntireDir(f string) (bool, error) {
+	// An exclusion might carve files out of a matching dir.
+	if m.matcher.Exclusions() {
+		return false, nil
+	}
+	return m.Matches(f)
+}
+
+// NewRelativeGlobMatcher returns a matcher for the given patterns (with any
+// relative patterns converted to absolute, relative to the given baseDir).
+func NewRelativeGlobMatcher(baseDir string, patterns ...string) (globMatcher, error) {
+	absPatterns := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		exclusion := strings.HasPrefix(p, "!")
+		p = strings.TrimPrefix(p, "!")
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(baseDir, p)
+		}
+		if exclusion {
+			p = "!" + p
+		}
+		absPatterns = append(absPatterns, p)
+	}
+
+	pm, err := dockerignore.NewPatternMatcher(absPatterns)
+	if err != nil {
+		return globMatcher{}, errors.Wrap(err, "NewRelativeGlobMatcher")
+	}
+	return globMatcher{matcher: pm}, nil
+}
+
 // A PathSet stores one or more filepaths, along with the directory that any
 // relative paths are relative to
 // NOTE(maia): in its current usage (for LiveUpdate.Run.Triggers, LiveUpdate.FallBackOnFiles())
@@ -117,8 +161,20 @@ func (ps PathSet) Empty() bool { return len(ps.Paths) == 0 }
 // AnyMatch returns true if any of the given filepaths match any paths contained in the pathset
 // (along with the first path that matched).
 func (ps PathSet) AnyMatch(paths []string) (bool, string, error) {
-	matcher := NewRelativeFileOrChildMatcher(ps.BaseDirectory, ps.Paths...)
+	return anyMatch(NewRelativeFileOrChildMatcher(ps.BaseDirectory, ps.Paths...), paths)
+}
+
+// AnyGlobMatch is like AnyMatch, but treats the paths in the pathset
+// as dockerignore-style patterns (see globMatcher).
+func (ps PathSet) AnyGlobMatch(paths []string) (bool, string, error) {
+	matcher, err := NewRelativeGlobMatcher(ps.BaseDirectory, ps.Paths...)
+	if err != nil {
+		return false, "", err
+	}
+	return anyMatch(matcher, paths)
+}
 
+func anyMatch(matcher PathMatcher, paths []string) (bool, string, error) {
 	for _, path := range paths {
 		match, err := matcher.Matches(path)
 		if err != nil {
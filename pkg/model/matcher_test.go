@@ -56,3 +56,57 @@ func TestFileOrChildMatcher(t *testing.T) {
 		}
 	}
 }
+
+func TestGlobMatcher(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	matcher, err := NewRelativeGlobMatcher(f.Path(),
+		"package.json",
+		"config",
+		"*.lock",
+		"**/*.go",
+		"!vendor",
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// map test case --> expected match
+	expectedMatch := map[string]bool{
+		"package.json":           true,
+		"nested/package.json":    false,
+		"config/settings.yaml":   true,
+		"yarn.lock":              true,
+		"nested/yarn.lock":       false,
+		"main.go":                true,
+		"cmd/server/main.go":     true,
+		"vendor/dep/dep.go":      false,
+		"src/index.js":           false,
+		"cmd/server/main.go.bak": false,
+	}
+
+	for file, expected := range expectedMatch {
+		match, err := matcher.Matches(f.JoinPath(file))
+		if assert.NoError(t, err) {
+			assert.Equal(t, expected, match, "expected file '%s' match --> %t", file, expected)
+		}
+	}
+}
+
+func TestPathSetAnyGlobMatch(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	defer f.TearDown()
+
+	ps := NewPathSet([]string{f.JoinPath("**/*.go")}, f.Path())
+
+	match, file, err := ps.AnyGlobMatch([]string{f.JoinPath("README.md"), f.JoinPath("cmd/main.go")})
+	assert.NoError(t, err)
+	assert.True(t, match)
+	assert.Equal(t, f.JoinPath("cmd/main.go"), file)
+
+	// A glob that matches nothing isn't an error
+	match, _, err = ps.AnyGlobMatch([]string{f.JoinPath("README.md")})
+	assert.NoError(t, err)
+	assert.False(t, match)
+}
@@ -11,8 +11,14 @@ const (
 )
 
 type UpdateSettings struct {
-	maxParallelUpdates int           // max number of updates to run concurrently
-	k8sUpsertTimeout   time.Duration // timeout for k8s upsert operations
+	maxParallelUpdates         int           // max number of updates to run concurrently
+	k8sUpsertTimeout           time.Duration // timeout for k8s upsert operations
+	liveUpdateMaxFiles         int           // max number of files in a single live update (0 = unlimited)
+	liveUpdateMaxBytes         int64         // max bytes copied in a single live update (0 = unlimited)
+	liveUpdateSkipRuns         bool          // if true, live updates only sync files and never execute run steps
+	liveUpdateStrictSyncs      bool          // if true, live updates fail (instead of warning) when files collide on a container path
+	liveUpdateCompressMinBytes int64         // gzip live update archives with at least this many bytes (0 = never compress)
+	liveUpdateTolerateFailure  bool          // if true, a run step failing on some containers doesn't trigger a full rebuild
 }
 
 func (us UpdateSettings) MaxParallelUpdates() int {
@@ -49,6 +55,84 @@ func (us UpdateSettings) WithK8sUpsertTimeout(timeout time.Duration) UpdateSetti
 	return us
 }
 
+// LiveUpdateMaxFiles is the max number of files a single live update may
+// sync before we fall back to an image build. 0 means unlimited.
+func (us UpdateSettings) LiveUpdateMaxFiles() int {
+	return us.liveUpdateMaxFiles
+}
+
+func (us UpdateSettings) WithLiveUpdateMaxFiles(n int) UpdateSettings {
+	if n < 0 {
+		n = 0
+	}
+	us.liveUpdateMaxFiles = n
+	return us
+}
+
+// LiveUpdateMaxBytes is the max number of bytes a single live update may
+// copy before we fall back to an image build. 0 means unlimited.
+func (us UpdateSettings) LiveUpdateMaxBytes() int64 {
+	return us.liveUpdateMaxBytes
+}
+
+func (us UpdateSettings) WithLiveUpdateMaxBytes(n int64) UpdateSettings {
+	if n < 0 {
+		n = 0
+	}
+	us.liveUpdateMaxBytes = n
+	return us
+}
+
+// LiveUpdateSkipRuns is true if live updates should sync files without
+// executing any run steps (e.g., in CI, or for containers that serve
+// static assets).
+func (us UpdateSettings) LiveUpdateSkipRuns() bool {
+	return us.liveUpdateSkipRuns
+}
+
+func (us UpdateSettings) WithLiveUpdateSkipRuns(skip bool) UpdateSettings {
+	us.liveUpdateSkipRuns = skip
+	return us
+}
+
+// LiveUpdateStrictSyncs is true if a live update should fail when two changed
+// files would be synced to the same container path. Otherwise, we warn and
+// the last file copied wins.
+func (us UpdateSettings) LiveUpdateStrictSyncs() bool {
+	return us.liveUpdateStrictSyncs
+}
+
+func (us UpdateSettings) WithLiveUpdateStrictSyncs(strict bool) UpdateSettings {
+	us.liveUpdateStrictSyncs = strict
+	return us
+}
+
+// LiveUpdateCompressMinBytes is the smallest live update (in bytes of files
+// copied) that we'll gzip before sending to the container. Compressing helps
+// on remote clusters, but isn't worth the CPU for small updates. 0 means
+// never compress.
+func (us UpdateSettings) LiveUpdateCompressMinBytes() int64 {
+	return us.liveUpdateCompressMinBytes
+}
+
+func (us UpdateSettings) WithLiveUpdateCompressMinBytes(n int64) UpdateSettings {
+	us.liveUpdateCompressMinBytes = n
+	return us
+}
+
+// LiveUpdateToleratePartialFailure is true if a run step that fails on some
+// containers, but succeeds on others, should be reported as a failed live
+// update instead of falling back to a full rebuild. Useful for idempotent
+// run steps that will succeed on the next change.
+func (us UpdateSettings) LiveUpdateToleratePartialFailure() bool {
+	return us.liveUpdateTolerateFailure
+}
+
+func (us UpdateSettings) WithLiveUpdateToleratePartialFailure(tolerate bool) UpdateSettings {
+	us.liveUpdateTolerateFailure = tolerate
+	return us
+}
+
 func DefaultUpdateSettings() UpdateSettings {
 	return UpdateSettings{
 		maxParallelUpdates: DefaultMaxParallelUpdates,
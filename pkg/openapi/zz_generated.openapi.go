@@ -1213,7 +1213,7 @@ func schema_pkg_apis_core_v1alpha1_IgnoreDef(ref common.ReferenceCallback) commo
 				Properties: map[string]spec.Schema{
 					"basePath": {
 						SchemaProps: spec.SchemaProps{
-							Description: "BasePath is the base path for the patterns. It cannot be empty.\n\nIf no patterns are specified, everything under it will be recursively ignored.",
+							Description: "BasePath is the base path for the patterns. It cannot be empty.\n\nIf no patterns or regexes are specified, everything under it will be recursively ignored.",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
@@ -1234,6 +1234,21 @@ func schema_pkg_apis_core_v1alpha1_IgnoreDef(ref common.ReferenceCallback) commo
 							},
 						},
 					},
+					"regexes": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Regexes are Go regular expressions (see https://golang.org/pkg/regexp/syntax/). A path is ignored if any regex matches its path relative to the BasePath, using forward slashes as separators.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"basePath"},
 			},
@@ -2141,6 +2156,20 @@ func schema_pkg_apis_core_v1alpha1_Pod(ref common.ReferenceCallback) common.Open
 							},
 						},
 					},
+					"ephemeralContainers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EphemeralContainers are containers added to the running Pod (e.g., with `kubectl debug`).",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1.Container"),
+									},
+								},
+							},
+						},
+					},
 					"ancestorUID": {
 						SchemaProps: spec.SchemaProps{
 							Description: "AncestorUID is the UID from the WatchRef that matched this Pod.\n\nIf the Pod matched based on extra label selectors, this will be empty.",
@@ -3334,6 +3363,13 @@ func schema_pkg_apis_core_v1alpha1_UIBuildTerminated(ref common.ReferenceCallbac
 							Format:      "",
 						},
 					},
+					"fallBackReason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If a live update fell back to this full build, why.\n\nOne of: stop-path, no-match, exec-error, limit.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -3952,6 +3988,13 @@ func schema_pkg_apis_core_v1alpha1_UIResourceStatus(ref common.ReferenceCallback
 							Format:      "int32",
 						},
 					},
+					"liveUpdatePaused": {
+						SchemaProps: spec.SchemaProps{
+							Description: "True if the user paused live updates for this resource. File changes that would be live-updated are skipped until they resume.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -418,6 +418,13 @@ declare namespace Proto {
      * +optional
      */
     order?: number;
+    /**
+     * True if the user paused live updates for this resource. File changes
+     * that would be live-updated are skipped until they resume.
+     *
+     * +optional
+     */
+    liveUpdatePaused?: boolean;
   }
   export interface v1alpha1UIResourceSpec {}
   export interface v1alpha1UIResourceLocal {
@@ -519,6 +526,7 @@ declare namespace Proto {
     finishTime?: string;
     spanID?: string;
     isCrashRebuild?: boolean;
+    fallBackReason?: string;
   }
   export interface v1alpha1UIBuildRunning {
     startTime?: string;